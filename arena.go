@@ -0,0 +1,124 @@
+package goparsify
+
+// arenaBlockSize is how many Results each block of an Arena holds
+const arenaBlockSize = 512
+
+// Arena hands out the .Child slices of Results from a few big blocks, instead of allocating each one on its own,
+// and takes back the ones built by attempts that were backtracked over. It is opt in, set State.Arena before
+// running a parser with RunState:
+//  ps := NewState(input)
+//  ps.Arena = arena
+//  result, err := RunState(parser, ps)
+//  arena.Release()
+//
+// Every Result tree built from an Arena is only valid until Release is called, which is what lets it be used
+// again for the next parse. Values built by Map, like the .Result of the root, are fine to keep as long as they
+// don't hold on to .Child. An Arena can only be used by one parse at a time, use a sync.Pool to share them.
+type Arena struct {
+	blocks [][]Result
+	// the next free Result is blocks[top.block][top.used]
+	top arenaMark
+	// results below floor have been kept by Memo or LeftRec, so they can't be taken back until Release
+	floor arenaMark
+}
+
+type arenaMark struct {
+	block, used int
+}
+
+func (m arenaMark) before(other arenaMark) bool {
+	return m.block < other.block || m.block == other.block && m.used < other.used
+}
+
+// NewArena creates an empty Arena, it grows as it is used
+func NewArena() *Arena {
+	return &Arena{}
+}
+
+// alloc returns n zeroed Results, or a new slice when there is no arena
+func (a *Arena) alloc(n int) []Result {
+	if a == nil {
+		return make([]Result, n)
+	}
+
+	for a.top.block < len(a.blocks) && a.top.used+n > len(a.blocks[a.top.block]) {
+		a.top = arenaMark{a.top.block + 1, 0}
+	}
+	if a.top.block == len(a.blocks) {
+		size := arenaBlockSize
+		if n > size {
+			size = n
+		}
+		a.blocks = append(a.blocks, make([]Result, size))
+	}
+
+	block := a.blocks[a.top.block]
+	ret := block[a.top.used : a.top.used+n : a.top.used+n]
+	a.top.used += n
+	return ret
+}
+
+// grow returns a slice with the same Results as results and room for more
+func (a *Arena) grow(results []Result) []Result {
+	n := 2 * cap(results)
+	if n == 0 {
+		n = 4
+	}
+	grown := a.alloc(n)
+	copy(grown, results)
+	return grown[:len(results)]
+}
+
+func (a *Arena) mark() arenaMark {
+	if a == nil {
+		return arenaMark{}
+	}
+	return a.top
+}
+
+// reset takes back everything handed out since mark, unless it has been kept
+func (a *Arena) reset(mark arenaMark) {
+	if a == nil {
+		return
+	}
+	if mark.before(a.floor) {
+		mark = a.floor
+	}
+	if !mark.before(a.top) {
+		return
+	}
+	a.clear(mark, a.top)
+	a.top = mark
+}
+
+// keep stops everything handed out so far from being taken back by reset
+func (a *Arena) keep() {
+	if a != nil {
+		a.floor = a.top
+	}
+}
+
+// clear zeroes the Results between from and to, so alloc can hand them out again without holding on to anything
+func (a *Arena) clear(from, to arenaMark) {
+	for b := from.block; b <= to.block && b < len(a.blocks); b++ {
+		block := a.blocks[b]
+		start, end := 0, len(block)
+		if b == from.block {
+			start = from.used
+		}
+		if b == to.block {
+			end = to.used
+		}
+		for i := start; i < end; i++ {
+			block[i] = Result{}
+		}
+	}
+}
+
+// Release takes back every Result handed out, so the Arena can be used for the next parse. Result trees built
+// from it must not be used after this.
+func (a *Arena) Release() {
+	a.clear(arenaMark{}, a.top)
+	a.top = arenaMark{}
+	a.floor = arenaMark{}
+}
@@ -0,0 +1,129 @@
+package goparsify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArena(t *testing.T) {
+	t.Run("hands out zeroed results from blocks", func(t *testing.T) {
+		a := NewArena()
+		first := a.alloc(3)
+		require.Len(t, first, 3)
+		require.Equal(t, 3, cap(first))
+		first[0].Token = "x"
+
+		second := a.alloc(arenaBlockSize)
+		require.Len(t, second, arenaBlockSize)
+		require.Len(t, a.blocks, 2)
+		require.Equal(t, Result{}, second[0])
+	})
+
+	t.Run("reset takes results back", func(t *testing.T) {
+		a := NewArena()
+		a.alloc(2)
+		mark := a.mark()
+		taken := a.alloc(2)
+		taken[1].Token = "x"
+
+		a.reset(mark)
+		again := a.alloc(2)
+		require.Equal(t, &taken[0], &again[0])
+		require.Equal(t, Result{}, again[1])
+	})
+
+	t.Run("kept results aren't taken back", func(t *testing.T) {
+		a := NewArena()
+		mark := a.mark()
+		kept := a.alloc(1)
+		kept[0].Token = "x"
+		a.keep()
+
+		a.reset(mark)
+		require.Equal(t, "x", kept[0].Token)
+		require.NotEqual(t, &kept[0], &a.alloc(1)[0])
+	})
+
+	t.Run("grow keeps what was there", func(t *testing.T) {
+		a := NewArena()
+		results := a.alloc(2)[:1]
+		results[0].Token = "x"
+		results = a.grow(results)
+		require.Len(t, results, 1)
+		require.Equal(t, 4, cap(results))
+		require.Equal(t, "x", results[0].Token)
+	})
+
+	t.Run("release clears everything", func(t *testing.T) {
+		a := NewArena()
+		results := a.alloc(2)
+		results[0].Token = "x"
+		a.keep()
+
+		a.Release()
+		require.Equal(t, Result{}, results[0])
+		require.Equal(t, &results[0], &a.alloc(1)[0])
+	})
+
+	t.Run("works without an arena", func(t *testing.T) {
+		var a *Arena
+		require.Len(t, a.alloc(2), 2)
+		a.reset(a.mark())
+		a.keep()
+	})
+}
+
+func TestParsingWithArena(t *testing.T) {
+	run := func(p Parser, input string) (*State, Result) {
+		ps := NewState(input)
+		ps.Arena = NewArena()
+		node := Result{}
+		p(ps, &node)
+		return ps, node
+	}
+
+	t.Run("builds the same results", func(t *testing.T) {
+		p := Some(Any(Seq("a", "b", "c"), Seq("a", Many(Chars("b"), ","))), ";")
+		input := "abc; a b,b; abc"
+
+		ps, node := run(p, input)
+		plainNode, plain := runParser(input, p)
+		require.False(t, ps.Errored())
+		require.Equal(t, plain.Pos, ps.Pos)
+		require.Equal(t, plainNode, node)
+	})
+
+	t.Run("backtracking gives results back", func(t *testing.T) {
+		p := Any(Seq("a", Seq("b", "c"), "x"), Seq("a", "b"))
+		ps, node := run(p, "abc")
+		require.False(t, ps.Errored())
+		require.Equal(t, "b", node.Child[1].Token)
+		require.Equal(t, arenaMark{0, 2}, ps.Arena.top)
+	})
+
+	t.Run("failed sequences don't keep their children", func(t *testing.T) {
+		ps, node := run(Maybe(Seq("a", "b")), "ax")
+		require.False(t, ps.Errored())
+		require.Nil(t, node.Child)
+		require.Equal(t, arenaMark{}, ps.Arena.top)
+	})
+
+	t.Run("memoized results stay valid", func(t *testing.T) {
+		term := Memo(Seq("(", Chars("0-9"), ")"))
+		p := Any(Seq(term, "+", term), Seq(term, "-"))
+		ps, node := run(p, "(1)-")
+		require.False(t, ps.Errored())
+		require.Equal(t, "1", node.Child[0].Child[1].Token)
+	})
+
+	t.Run("RunState", func(t *testing.T) {
+		ps := NewState("a b")
+		ps.Arena = NewArena()
+		result, err := RunState(Many(Chars("a-z")).Map(func(n *Result) {
+			n.Result = len(n.Child)
+		}), ps)
+		require.NoError(t, err)
+		require.Equal(t, 2, result)
+	})
+}
@@ -0,0 +1,61 @@
+package goparsify
+
+import (
+	"strings"
+	"unsafe"
+)
+
+// NewStateBytes creates a new State that parses input without copying it. Input is a string that shares its
+// memory with the slice, so the slice must not be modified while the State is in use, or while any Token, Trivia
+// or error that came from it is. Set CopyTokens when they need to outlive the slice.
+func NewStateBytes(input []byte) *State {
+	s := NewState(bytesToString(input))
+	s.borrowed = &borrowedInput{}
+	return s
+}
+
+// RunBytes is Run for input in a []byte, without copying it, see NewStateBytes. The result is only valid for as
+// long as input isn't modified, use RunState with CopyTokens set when it needs to be kept.
+func RunBytes(parser Parserish, input []byte, ws ...VoidParser) (result interface{}, err error) {
+	return run(Parsify(parser), NewStateBytes(input), ws)
+}
+
+// bytesToString returns a string that uses the same memory as b. A string is the first two words of a slice.
+func bytesToString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return *(*string)(unsafe.Pointer(&b))
+}
+
+// token is Input[start:end], copied when CopyTokens is set
+func (s *State) token(start, end int) string {
+	if !s.CopyTokens {
+		return s.Input[start:end]
+	}
+	return copyString(s.Input[start:end])
+}
+
+// detach returns a copy of input for errors to keep, when it is borrowed and CopyTokens is set. The copy is made
+// once and shared by every error.
+func (s *State) detach(input string) string {
+	if s.borrowed == nil || !s.CopyTokens {
+		return input
+	}
+	if s.borrowed.copy == "" {
+		s.borrowed.copy = copyString(s.Input)
+	}
+	return s.borrowed.copy
+}
+
+// borrowedInput is set on a State whose Input shares its memory with the []byte given to NewStateBytes
+type borrowedInput struct {
+	// copy of Input made for errors by detach
+	copy string
+}
+
+func copyString(s string) string {
+	var b strings.Builder
+	b.WriteString(s)
+	return b.String()
+}
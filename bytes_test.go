@@ -0,0 +1,68 @@
+package goparsify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunBytes(t *testing.T) {
+	words := Many(Chars("a-z")).Map(func(n *Result) {
+		var tokens []string
+		for _, child := range n.Child {
+			tokens = append(tokens, child.Token)
+		}
+		n.Result = tokens
+	})
+
+	t.Run("parses without copying", func(t *testing.T) {
+		buf := []byte("hello world")
+		result, err := RunBytes(words, buf)
+		require.NoError(t, err)
+		require.Equal(t, []string{"hello", "world"}, result)
+
+		// tokens are views of the buffer, so they change with it
+		copy(buf, "jello")
+		require.Equal(t, []string{"jello", "world"}, result)
+	})
+
+	t.Run("CopyTokens keeps tokens apart from the buffer", func(t *testing.T) {
+		buf := []byte("hello world")
+		ps := NewStateBytes(buf)
+		ps.CopyTokens = true
+		result, err := RunState(words, ps)
+		require.NoError(t, err)
+
+		copy(buf, "jello")
+		require.Equal(t, []string{"hello", "world"}, result)
+	})
+
+	t.Run("CopyTokens copies errors", func(t *testing.T) {
+		buf := []byte("hello 123")
+		ps := NewStateBytes(buf)
+		ps.CopyTokens = true
+		_, err := RunState(words, ps)
+		require.Error(t, err)
+
+		copy(buf, "xxxxxxxxx")
+		require.Equal(t, "123", err.(UnparsedInputError).Remaining())
+	})
+
+	t.Run("CopyTokens copies trivia", func(t *testing.T) {
+		buf := []byte(" a")
+		ps := NewStateBytes(buf)
+		ps.CopyTokens = true
+		node := Result{}
+		KeepTrivia(Chars("a-z"))(ps, &node)
+		require.False(t, ps.Errored())
+
+		copy(buf, "xx")
+		require.Equal(t, " ", node.Child[1].Result.(Trivia)[node.Child[0].Start])
+		require.Equal(t, "a", node.Child[0].Token)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		_, err := RunBytes(Maybe("a"), nil)
+		require.NoError(t, err)
+	})
+}
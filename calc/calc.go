@@ -9,9 +9,6 @@ import (
 var (
 	value Parser
 
-	sumOp  = Chars("+-", 1, 1)
-	prodOp = Chars("/*", 1, 1)
-
 	groupExpr = Seq("(", sum, ")").Map(func(n *Result) {
 		n.Result = n.Child[1].Result
 	})
@@ -27,35 +24,23 @@ var (
 		}
 	})
 
-	sum = Seq(prod, Some(Seq(sumOp, prod))).Map(func(n *Result) {
-		i := n.Child[0].Result.(float64)
-
-		for _, op := range n.Child[1].Child {
-			switch op.Child[0].Token {
-			case "+":
-				i += op.Child[1].Result.(float64)
-			case "-":
-				i -= op.Child[1].Result.(float64)
-			}
-		}
-
-		n.Result = i
-	})
-
-	prod = Seq(&value, Some(Seq(prodOp, &value))).Map(func(n *Result) {
-		i := n.Child[0].Result.(float64)
-
-		for _, op := range n.Child[1].Child {
-			switch op.Child[0].Token {
-			case "/":
-				i /= op.Child[1].Result.(float64)
-			case "*":
-				i *= op.Child[1].Result.(float64)
-			}
-		}
-
-		n.Result = i
-	})
+	sum = Expr(&value,
+		Infix(1, AssocLeft, "+", func(n *Result) {
+			n.Result = n.Child[0].Result.(float64) + n.Child[2].Result.(float64)
+		}),
+		Infix(1, AssocLeft, "-", func(n *Result) {
+			n.Result = n.Child[0].Result.(float64) - n.Child[2].Result.(float64)
+		}),
+		Infix(2, AssocLeft, "*", func(n *Result) {
+			n.Result = n.Child[0].Result.(float64) * n.Child[2].Result.(float64)
+		}),
+		Infix(2, AssocLeft, "/", func(n *Result) {
+			n.Result = n.Child[0].Result.(float64) / n.Child[2].Result.(float64)
+		}),
+		Prefix(3, "-", func(n *Result) {
+			n.Result = -n.Child[1].Result.(float64)
+		}),
+	)
 
 	y = Maybe(sum)
 )
@@ -41,6 +41,19 @@ func TestOrderOfOperations(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, 21, result)
 }
+
+func TestLeftAssociative(t *testing.T) {
+	result, err := calc(`8-4-2/2*4`)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, result)
+}
+
+func TestNegation(t *testing.T) {
+	result, err := calc(`-(1+2)*-2`)
+	require.NoError(t, err)
+	require.EqualValues(t, 6, result)
+}
+
 func TestParenthesis(t *testing.T) {
 	result, err := calc(`(1+10)*2`)
 	require.NoError(t, err)
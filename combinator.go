@@ -2,48 +2,72 @@ package goparsify
 
 import (
 	"bytes"
+	"sync"
 )
 
 // Seq matches all of the given parsers in order and returns their result as .Child[n]
 func Seq(parsers ...Parserish) Parser {
 	parserfied := ParsifyAll(parsers...)
 
-	return NewParser("Seq()", func(ps *State, node *Result) {
-		node.Child = make([]Result, len(parserfied))
-		startpos := ps.Pos
+	return describe(NewParser("Seq()", func(ps *State, node *Result) {
+		if !ps.step(len(parserfied)) {
+			return
+		}
+		mark := ps.Arena.mark()
+		node.Child = ps.Arena.alloc(len(parserfied))
+		startpos, recovered := ps.Offset+ps.Pos, len(ps.Recovered)
 		for i, parser := range parserfied {
 			parser(ps, &node.Child[i])
 			if ps.Errored() {
-				ps.Pos = startpos
+				ps.rewind(startpos)
+				ps.forget(recovered)
+				ps.reclaim(mark, node)
 				return
 			}
 		}
+		node.spanChildren(ps.Offset + ps.Pos)
+	}), func() *description {
+		return &description{kind: seqKind, children: lookupDescriptions(parserfied)}
 	})
 }
 
 // NoAutoWS disables automatically ignoring whitespace between tokens for all parsers underneath
 func NoAutoWS(parser Parserish) Parser {
 	parserfied := Parsify(parser)
-	return func(ps *State, node *Result) {
+	return describeAs(func(ps *State, node *Result) {
 		oldWS := ps.WS
 		ps.WS = NoWhitespace
 		parserfied(ps, node)
 		ps.WS = oldWS
-	}
+	}, parserfied)
 }
 
 // Any matches the first successful parser and returns its result
 func Any(parsers ...Parserish) Parser {
 	parserfied := ParsifyAll(parsers...)
-	// Records which parser was successful for each byte, and will use it first next time.
+	// Works out which alternatives could match each byte from their descriptions, so the rest can be skipped. This
+	// is done on the first call, once every *Parser the alternatives refer to has been set. Without descriptions
+	// every alternative is tried in order.
+	dispatch := recordingDescriptions()
+	var table *dispatchTable
+	var once sync.Once
+	build := func() {
+		table = newDispatchTable(parserfied)
+	}
 
-	return NewParser("Any()", func(ps *State, node *Result) {
+	return describe(NewParser("Any()", func(ps *State, node *Result) {
+		if !ps.step(0) {
+			return
+		}
 		ps.WS(ps)
-		if ps.Pos >= len(ps.Input) {
+		if ps.Errored() {
+			return
+		}
+		if !ps.has(ps.Pos) {
 			ps.ErrorHere("!EOF")
 			return
 		}
-		startpos := ps.Pos
+		startpos := ps.Offset + ps.Pos
 
 		longestError := ps.Error
 		if ps.Cut <= startpos {
@@ -52,7 +76,28 @@ func Any(parsers ...Parserish) Parser {
 			return
 		}
 
-		for _, parser := range parserfied {
+		candidates, skipped := ^uint64(0), [][]string(nil)
+		if dispatch {
+			once.Do(build)
+			if table != nil {
+				candidates, skipped = table.candidates[ps.Input[ps.Pos]], table.expected
+			}
+		}
+
+		for i, parser := range parserfied {
+			if i < 64 && candidates&(1<<uint(i)) == 0 {
+				// it could only have failed right here, so expect what it would have without running it
+				for _, expected := range skipped[i] {
+					ps.ErrorHere(expected)
+				}
+				if ps.Error.pos >= longestError.pos {
+					longestError = ps.Error
+				}
+				ps.Recover()
+				continue
+			}
+
+			mark, recovered := ps.Arena.mark(), len(ps.Recovered)
 			parser(ps, node)
 			if ps.Errored() {
 				if ps.Error.pos >= longestError.pos {
@@ -62,13 +107,22 @@ func Any(parsers ...Parserish) Parser {
 					break
 				}
 				ps.Recover()
+				ps.forget(recovered)
+				// dont leave anything from this attempt behind for the next one
+				ps.Arena.reset(mark)
+				*node = Result{}
 				continue
 			}
+
+			node.Start = startpos
+			node.End = ps.Offset + ps.Pos
 			return
 		}
 
 		ps.Error = longestError
-		ps.Pos = startpos
+		ps.rewind(startpos)
+	}), func() *description {
+		return &description{kind: anyKind, children: lookupDescriptions(parserfied)}
 	})
 }
 
@@ -76,14 +130,14 @@ func Any(parsers ...Parserish) Parser {
 // an optional separator can be provided and that value will be consumed
 // but not returned. Only one separator can be provided.
 func Some(parser Parserish, separator ...Parserish) Parser {
-	return NewParser("Some()", manyImpl(0, parser, separator...))
+	return describe(NewParser("Some()", manyImpl(0, parser, separator...)), repeatDescription(someKind, parser, separator))
 }
 
 // Many matches zero or more parsers and returns the value as .Child[n]
 // an optional separator can be provided and that value will be consumed
 // but not returned. Only one separator can be provided.
 func Many(parser Parserish, separator ...Parserish) Parser {
-	return NewParser("Many()", manyImpl(1, parser, separator...))
+	return describe(NewParser("Many()", manyImpl(1, parser, separator...)), repeatDescription(manyKind, parser, separator))
 }
 
 func manyImpl(min int, op Parserish, sep ...Parserish) Parser {
@@ -94,18 +148,34 @@ func manyImpl(min int, op Parserish, sep ...Parserish) Parser {
 	}
 
 	return func(ps *State, node *Result) {
-		node.Child = make([]Result, 0, 5)
-		startpos := ps.Pos
+		start := ps.Arena.mark()
+		node.Child = ps.Arena.alloc(5)[:0]
+		startpos, recovered := ps.Offset+ps.Pos, len(ps.Recovered)
 		for {
-			node.Child = append(node.Child, Result{})
+			if len(node.Child) == cap(node.Child) {
+				node.Child = ps.Arena.grow(node.Child)
+			}
+			if !ps.step(1) {
+				ps.rewind(startpos)
+				ps.forget(recovered)
+				ps.reclaim(start, node)
+				return
+			}
+			node.Child = node.Child[:len(node.Child)+1]
+			itempos, attempt, attemptRecovered := ps.Offset+ps.Pos, ps.Arena.mark(), len(ps.Recovered)
 			opParser(ps, &node.Child[len(node.Child)-1])
 			if ps.Errored() {
-				if len(node.Child)-1 < min || ps.Cut > ps.Pos {
-					ps.Pos = startpos
+				if len(node.Child)-1 < min || ps.Cut > itempos {
+					ps.rewind(startpos)
+					ps.forget(recovered)
+					ps.reclaim(start, node)
 					return
 				}
 				ps.Recover()
+				ps.forget(attemptRecovered)
+				ps.Arena.reset(attempt)
 				node.Child = node.Child[0 : len(node.Child)-1]
+				node.spanChildren(ps.Offset + ps.Pos)
 				return
 			}
 
@@ -113,6 +183,7 @@ func manyImpl(min int, op Parserish, sep ...Parserish) Parser {
 				sepParser(ps, TrashResult)
 				if ps.Errored() {
 					ps.Recover()
+					node.spanChildren(ps.Offset + ps.Pos)
 					return
 				}
 			}
@@ -124,28 +195,117 @@ func manyImpl(min int, op Parserish, sep ...Parserish) Parser {
 func Maybe(parser Parserish) Parser {
 	parserfied := Parsify(parser)
 
-	return NewParser("Maybe()", func(ps *State, node *Result) {
-		startpos := ps.Pos
+	return describe(NewParser("Maybe()", func(ps *State, node *Result) {
+		startpos, recovered := ps.Offset+ps.Pos, len(ps.Recovered)
 		parserfied(ps, node)
 		if ps.Errored() && ps.Cut <= startpos {
 			ps.Recover()
+			ps.forget(recovered)
+		}
+	}), func() *description {
+		return &description{kind: maybeKind, children: lookupDescriptions([]Parser{parserfied})}
+	})
+}
+
+// Label gives a parser a human friendly name for error messages. When the parser fails
+// at its start position the error will expect name instead of whatever the parser was
+// looking for, eg Label(Regex("[a-z]+"), "identifier") expects "identifier".
+func Label(parser Parserish, name string) Parser {
+	p := Parsify(parser)
+
+	return describe(NewParser(name, func(ps *State, node *Result) {
+		startpos := ps.Offset + ps.Pos
+		furthest, expecting := ps.furthest, len(ps.expecting)
+
+		p(ps, node)
+		if !ps.Errored() {
+			return
+		}
+
+		ps.rewind(startpos)
+		ps.WS(ps)
+		labelpos := ps.Offset + ps.Pos
+		ps.rewind(startpos)
+
+		// once the parser has made some progress its own errors are more useful than the label
+		if ps.Error.pos != labelpos {
+			return
+		}
+
+		if ps.furthest == labelpos {
+			if furthest == labelpos {
+				ps.expecting = ps.expecting[:expecting]
+			} else {
+				ps.expecting = ps.expecting[:0]
+			}
+		}
+		ps.ErrorAt(labelpos-ps.Offset, name)
+	}), func() *description {
+		return &description{kind: namedKind, text: name, children: lookupDescriptions([]Parser{p}), expected: []string{name}}
+	})
+}
+
+// Not matches, without consuming anything, when parser does not match here. This is useful for keywords that
+// should not be the start of a longer identifier, eg Seq("if", Not(Chars("a-z0-9_", 1))), or for matching anything
+// but a terminator, eg Some(Seq(Not("*/"), anyChar)). When parser does match the error expects "not" whatever it
+// matched, eg not 'def' for the keyword above given ifdef.
+func Not(parser Parserish) Parser {
+	p := Parsify(parser)
+
+	return describe(NewParser("Not()", func(ps *State, node *Result) {
+		startpos := ps.lookahead(p, node)
+		if ps.Errored() {
+			ps.Recover()
+			*node = Result{Start: startpos, End: startpos}
+			return
+		}
+
+		pos, matched := startpos-ps.Offset, ""
+		if node.End > node.Start {
+			pos = node.Start - ps.Offset
+			matched = ps.Input[pos : node.End-ps.Offset]
 		}
+		*node = Result{}
+		ps.ErrorAt(pos, "not '"+matched+"'")
+	}), func() *description {
+		return &description{kind: notKind, children: lookupDescriptions([]Parser{p})}
 	})
 }
 
+// Peek matches parser without consuming anything, leaving its result in the node.
+func Peek(parser Parserish) Parser {
+	p := Parsify(parser)
+
+	return describe(NewParser("Peek()", func(ps *State, node *Result) {
+		startpos := ps.lookahead(p, node)
+		if ps.Errored() {
+			ps.expect(ps.Error.pos, ps.Error.expected)
+			return
+		}
+		node.Start, node.End = startpos, startpos
+	}), func() *description {
+		return &description{kind: peekKind, children: lookupDescriptions([]Parser{p})}
+	})
+}
+
+// FollowedBy is Peek, for grammars that read better with it, eg Seq(identifier, FollowedBy("("))
+func FollowedBy(parser Parserish) Parser {
+	return Peek(parser)
+}
+
 // Bind will set the node .Result when the given parser matches
 // This is useful for giving a value to keywords and constant literals
 // like true and false. See the json parser for an example.
 func Bind(parser Parserish, val interface{}) Parser {
 	p := Parsify(parser)
 
-	return func(ps *State, node *Result) {
+	return describeAs(func(ps *State, node *Result) {
 		p(ps, node)
 		if ps.Errored() {
 			return
 		}
 		node.Result = val
-	}
+	}, p)
 }
 
 // Map applies the callback if the parser matches. This is used to set the Result
@@ -153,13 +313,13 @@ func Bind(parser Parserish, val interface{}) Parser {
 func Map(parser Parserish, f func(n *Result)) Parser {
 	p := Parsify(parser)
 
-	return func(ps *State, node *Result) {
+	return describeAs(func(ps *State, node *Result) {
 		p(ps, node)
 		if ps.Errored() {
 			return
 		}
 		f(node)
-	}
+	}, p)
 }
 
 func flatten(n *Result) {
@@ -20,7 +20,7 @@ func TestSeq(t *testing.T) {
 
 	t.Run("returns errors", func(t *testing.T) {
 		_, p2 := runParser("hello there", parser)
-		require.Equal(t, "world", p2.Error.expected)
+		require.Equal(t, "'world'", p2.Error.expected)
 		require.Equal(t, 6, p2.Error.pos)
 		require.Equal(t, 0, p2.Pos)
 	})
@@ -54,11 +54,17 @@ func TestAny(t *testing.T) {
 			Seq("hello", "world", "."),
 			Seq("hello", "brother"),
 		))
-		require.Equal(t, "offset 11: expected .", p2.Error.Error())
+		require.Equal(t, "'.'", p2.Error.expected)
 		require.Equal(t, 11, p2.Error.Pos())
 		require.Equal(t, 0, p2.Pos)
 	})
 
+	t.Run("Forgets failed alternatives", func(t *testing.T) {
+		node, _ := runParser("hello world!", Any(Seq("hello", "there"), "hello"))
+		require.Equal(t, "hello", node.Token)
+		require.Nil(t, node.Child)
+	})
+
 	t.Run("Accepts nil matches", func(t *testing.T) {
 		node, p2 := runParser("hello world!", Any(Exact("ffffff")))
 		require.Equal(t, Result{}, node)
@@ -88,6 +94,70 @@ func TestAny(t *testing.T) {
 
 		})
 	})
+
+	t.Run("skips alternatives that cant start with the next byte", func(t *testing.T) {
+		defer recordDescriptions()()
+
+		calls := 0
+		counted := func(p Parserish) Parser {
+			parser := Parsify(p)
+			return describeAs(func(ps *State, node *Result) {
+				calls++
+				parser(ps, node)
+			}, parser)
+		}
+		p := Any(counted("null"), counted(NumberLit()), counted(StringLit(`"`)))
+
+		node, ps := runParser(`"x"`, p)
+		require.False(t, ps.Errored())
+		require.Equal(t, "x", node.Token)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("tries every alternative in order without descriptions", func(t *testing.T) {
+		calls := 0
+		counted := func(p Parserish) Parser {
+			parser := Parsify(p)
+			return func(ps *State, node *Result) {
+				calls++
+				parser(ps, node)
+			}
+		}
+		p := Any(counted("null"), counted(NumberLit()), counted(StringLit(`"`)))
+
+		node, ps := runParser(`"x"`, p)
+		require.False(t, ps.Errored())
+		require.Equal(t, "x", node.Token)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("still expects every alternative", func(t *testing.T) {
+		defer recordDescriptions()()
+
+		_, err := Run(Any("null", NumberLit(), Seq("[", "]")), "nope")
+		require.Equal(t, []string{"'null'", "number", "'['"}, err.(*Error).Expected())
+	})
+
+	t.Run("tries alternatives that can match nothing", func(t *testing.T) {
+		defer recordDescriptions()()
+
+		node, ps := runParser("x", Any("a", Maybe("b")))
+		require.False(t, ps.Errored())
+		require.Equal(t, 0, ps.Pos)
+		require.Equal(t, Result{Start: 0, End: 0}, node)
+	})
+
+	t.Run("follows references set after it was built", func(t *testing.T) {
+		defer recordDescriptions()()
+
+		var value Parser
+		p := Any("a", Seq("[", &value, "]"))
+		value = Chars("0-9")
+
+		node, ps := runParser("[12]", p)
+		require.False(t, ps.Errored())
+		require.Equal(t, "12", node.Child[1].Token)
+	})
 }
 
 func TestSome(t *testing.T) {
@@ -147,7 +217,8 @@ func TestMany(t *testing.T) {
 
 	t.Run("Returns error if nothing matches", func(t *testing.T) {
 		_, p2 := runParser("a,b,c,d,e,", Many(Chars("def"), Exact(",")))
-		require.Equal(t, "offset 0: expected def", p2.Error.Error())
+		require.Equal(t, "def", p2.Error.expected)
+		require.Equal(t, 0, p2.Error.Pos())
 		require.Equal(t, "a,b,c,d,e,", p2.Get())
 	})
 }
@@ -168,9 +239,104 @@ func TestMap(t *testing.T) {
 
 	t.Run("error", func(t *testing.T) {
 		_, ps := runParser("<html", parser)
-		require.Equal(t, "offset 5: expected >", ps.Error.Error())
+		require.Equal(t, "'>'", ps.Error.expected)
+		require.Equal(t, 5, ps.Error.Pos())
+		require.Equal(t, 0, ps.Pos)
+	})
+}
+
+func TestLabel(t *testing.T) {
+	identifier := Label(Regex("[a-z][a-z0-9]*"), "identifier")
+
+	t.Run("success", func(t *testing.T) {
+		node, ps := runParser("foo1 bar", identifier)
+		require.Equal(t, "foo1", node.Token)
+		require.Equal(t, 4, ps.Pos)
+	})
+
+	t.Run("replaces the expectation", func(t *testing.T) {
+		_, ps := runParser("  1foo", identifier)
+		require.Equal(t, "identifier", ps.Error.expected)
+		require.Equal(t, 2, ps.Error.Pos())
 		require.Equal(t, 0, ps.Pos)
 	})
+
+	t.Run("replaces all inner expectations", func(t *testing.T) {
+		_, err := Run(Seq("<", Any(Label(Any("a", "b", Chars("0-9")), "tag name"), "/")), "<?")
+		require.Equal(t, []string{"tag name", "'/'"}, err.(*Error).Expected())
+	})
+
+	t.Run("keeps earlier expectations", func(t *testing.T) {
+		_, err := Run(Seq(Maybe("-"), identifier), "1")
+		require.Equal(t, []string{"'-'", "identifier"}, err.(*Error).Expected())
+	})
+
+	t.Run("keeps errors after the start", func(t *testing.T) {
+		_, ps := runParser("<a", Label(Seq("<", "a", ">"), "tag"))
+		require.Equal(t, "'>'", ps.Error.expected)
+		require.Equal(t, 2, ps.Error.Pos())
+		require.Equal(t, 0, ps.Pos)
+	})
+}
+
+func TestNot(t *testing.T) {
+	keyword := Seq("if", Not(Chars("a-z0-9_", 1)))
+
+	t.Run("success", func(t *testing.T) {
+		node, ps := runParser("if (x)", keyword)
+		require.False(t, ps.Errored())
+		require.Equal(t, 2, ps.Pos)
+		require.Equal(t, 0, node.Start)
+		require.Equal(t, 2, node.End)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, ps := runParser("ifdef", keyword)
+		require.Equal(t, "not 'def'", ps.Error.expected)
+		require.Equal(t, 2, ps.Error.Pos())
+		require.Equal(t, 0, ps.Pos)
+	})
+
+	t.Run("anything but a terminator", func(t *testing.T) {
+		comment := Seq("/*", Some(Seq(Not("*/"), NotChars("", 1, 1))), "*/")
+		_, ps := runParser("/* a * b */ c", comment)
+		require.False(t, ps.Errored())
+		require.Equal(t, " c", ps.Get())
+	})
+
+	t.Run("puts everything back", func(t *testing.T) {
+		_, ps := runParser("abc", Seq(Not(Seq("a", Cut(), "x")), "abc"))
+		require.False(t, ps.Errored())
+		require.Equal(t, 0, ps.Cut)
+		require.Equal(t, 3, ps.Pos)
+
+		_, err := Run(Any(Seq(Not("a"), "b"), "c"), "ax")
+		require.Equal(t, []string{"not 'a'", "'c'"}, err.(*Error).Expected())
+	})
+}
+
+func TestPeek(t *testing.T) {
+	call := Seq(Chars("a-z"), FollowedBy("("))
+
+	t.Run("success", func(t *testing.T) {
+		node, ps := runParser("foo(1)", call)
+		require.False(t, ps.Errored())
+		require.Equal(t, "(", node.Child[1].Token)
+		require.Equal(t, 3, ps.Pos)
+		require.Equal(t, 3, node.End)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := Run(call, "foo 1")
+		require.Equal(t, "1:5: expected '('\nfoo 1\n    ^", err.Error())
+	})
+
+	t.Run("puts everything back", func(t *testing.T) {
+		node, ps := runParser("abc", Seq(Peek(Seq("a", Cut(), "b")), "abc"))
+		require.False(t, ps.Errored())
+		require.Equal(t, 0, ps.Cut)
+		require.Equal(t, "abc", node.Child[1].Token)
+	})
 }
 
 func TestBind(t *testing.T) {
@@ -184,7 +350,8 @@ func TestBind(t *testing.T) {
 	t.Run("error", func(t *testing.T) {
 		result, ps := runParser("nil", parser)
 		require.Nil(t, result.Result)
-		require.Equal(t, "offset 0: expected true", ps.Error.Error())
+		require.Equal(t, "'true'", ps.Error.expected)
+		require.Equal(t, 0, ps.Error.Pos())
 		require.Equal(t, 0, ps.Pos)
 	})
 }
@@ -192,19 +359,22 @@ func TestBind(t *testing.T) {
 func TestCut(t *testing.T) {
 	t.Run("test any", func(t *testing.T) {
 		_, ps := runParser("var world", Any(Seq("var", Cut(), "hello"), "var world"))
-		require.Equal(t, "offset 4: expected hello", ps.Error.Error())
+		require.Equal(t, "'hello'", ps.Error.expected)
+		require.Equal(t, 4, ps.Error.Pos())
 		require.Equal(t, 0, ps.Pos)
 	})
 
 	t.Run("test many", func(t *testing.T) {
 		_, ps := runParser("hello <world", Many(Any(Seq("<", Cut(), Chars("a-z"), ">"), Chars("a-z"))))
-		require.Equal(t, "offset 12: expected >", ps.Error.Error())
+		require.Equal(t, "'>'", ps.Error.expected)
+		require.Equal(t, 12, ps.Error.Pos())
 		require.Equal(t, 0, ps.Pos)
 	})
 
 	t.Run("test maybe", func(t *testing.T) {
 		_, ps := runParser("var", Maybe(Seq("var", Cut(), "hello")))
-		require.Equal(t, "offset 3: expected hello", ps.Error.Error())
+		require.Equal(t, "'hello'", ps.Error.expected)
+		require.Equal(t, 3, ps.Error.Pos())
 		require.Equal(t, 0, ps.Pos)
 	})
 }
@@ -221,7 +391,8 @@ func TestMerge(t *testing.T) {
 
 	t.Run("error", func(t *testing.T) {
 		_, ps := runParser("((())", parser)
-		require.Equal(t, "offset 5: expected )", ps.Error.Error())
+		require.Equal(t, "')'", ps.Error.expected)
+		require.Equal(t, 5, ps.Error.Pos())
 		require.Equal(t, 0, ps.Pos)
 	})
 }
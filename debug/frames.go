@@ -13,10 +13,14 @@ import (
 var varRegex = regexp.MustCompile(`(?:var)?\s*(\w*)\s*:?=`)
 
 func getPackageName(f runtime.Frame) string {
-	parts := strings.Split(f.Func.Name(), ".")
+	// Func is nil for inlined calls, Function is always set. Generic functions end in [...]
+	parts := strings.Split(strings.Replace(f.Function, "[...]", "", -1), ".")
 	pl := len(parts)
+	if pl < 2 {
+		return f.Function
+	}
 
-	if parts[pl-2][0] == '(' {
+	if strings.HasPrefix(parts[pl-2], "(") {
 		return strings.Join(parts[0:pl-2], ".")
 	}
 
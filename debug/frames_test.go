@@ -1,6 +1,7 @@
 package debug
 
 import (
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -22,3 +23,18 @@ func TestRegex(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPackageName(t *testing.T) {
+	tests := map[string]string{
+		"github.com/vektah/goparsify.Seq":             "github.com/vektah/goparsify",
+		"github.com/vektah/goparsify.(*State).Get":    "github.com/vektah/goparsify",
+		"github.com/vektah/goparsify.MapTo[...]":      "github.com/vektah/goparsify",
+		"github.com/vektah/goparsify/json.init.func1": "github.com/vektah/goparsify/json.init",
+		"main": "main",
+	}
+	for function, expected := range tests {
+		t.Run(function, func(t *testing.T) {
+			require.Equal(t, expected, getPackageName(runtime.Frame{Function: function}))
+		})
+	}
+}
@@ -7,8 +7,9 @@ import "io"
 // NewParser should be called around the creation of every Parser.
 // It does nothing normally and should incur no runtime overhead, but when building with -tags debug
 // it will instrument every parser to collect valuable timing information displayable with DumpDebugStats.
+// After RecordDescriptions it also names the parser for Describe.
 func NewParser(description string, p Parser) Parser {
-	return p
+	return describeNamed(description, p, p)
 }
 
 // DumpDebugStats will print out the curring timings for each parser if built with -tags debug
@@ -124,7 +124,7 @@ func NewParser(name string, p Parser) Parser {
 	}
 
 	parsers = append(parsers, dp)
-	return dp.Parse
+	return describeNamed(name, dp.Parse, p)
 }
 
 // EnableLogging will write logs to the given writer as the next parse happens
@@ -0,0 +1,415 @@
+package goparsify
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/vektah/goparsify/debug"
+)
+
+type descriptionKind int
+
+const (
+	unknownKind descriptionKind = iota
+	emptyKind
+	exactKind
+	charsKind
+	regexKind
+	seqKind
+	anyKind
+	someKind
+	manyKind
+	maybeKind
+	notKind
+	peekKind
+	// namedKind is a parser given a name by NewParser or Label, with what it is made of as its child if known
+	namedKind
+	// refKind is a *Parser, which is only looked up when describing so recursive grammars can be built
+	refKind
+	leftRecKind
+)
+
+// description is the shape of a parser, recorded when it is built
+type description struct {
+	kind   descriptionKind
+	text   string
+	fold   bool
+	negate bool
+	// word is set for keywords, which only match whole words
+	word     bool
+	min, max int
+	children []*description
+	ref      *Parser
+	// first is every byte a parser without children can start with, when it is known, so Any can skip it
+	first string
+	// expected is what the parser says it expected when it fails where it starts, if it isn't the same as its
+	// children would say
+	expected []string
+	// location is where the parser was built, from debug.GetDefinition
+	location string
+}
+
+var descriptions struct {
+	on int32
+	sync.Mutex
+	// parsers has every parser built while recording, by the closure it is. The key keeps the closure alive, so
+	// its address can't be reused by something else while it is in here.
+	parsers map[unsafe.Pointer]describedParser
+}
+
+type describedParser struct {
+	desc *description
+	// inner is the parser that was described, so describing it again wraps inner instead of adding another call
+	inner Parser
+}
+
+// RecordDescriptions makes every parser built from now on remember what it is made of, so it can be passed to
+// Describe and Lint, and where it was built, so Lint can point at the line a problem comes from. It is off by
+// default because finding out where is slow, and because everything built while it is on is kept for as long as
+// the program runs. Call it before building the grammar, eg from an init function or at the start of a test.
+func RecordDescriptions() {
+	atomic.StoreInt32(&descriptions.on, 1)
+}
+
+func recordingDescriptions() bool {
+	return atomic.LoadInt32(&descriptions.on) == 1
+}
+
+// closureOf is what a Parser points at. A func value is a pointer to its closure, which is unique to each parser
+// returned by describe as they all capture the parser they wrap.
+func closureOf(p Parser) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&p))
+}
+
+// describe records what p is made of, when descriptions are being recorded. Otherwise p is returned as it is and
+// costs nothing.
+func describe(p Parser, desc func() *description) Parser {
+	if p == nil || !recordingDescriptions() {
+		return p
+	}
+	d := desc()
+	if d.location == "" {
+		_, d.location = debug.GetDefinition()
+	}
+
+	// wrapped so describing p again, like NewParser does, doesn't change what p itself is described as
+	inner := p
+	descriptions.Lock()
+	defer descriptions.Unlock()
+	if existing, ok := descriptions.parsers[closureOf(p)]; ok {
+		inner = existing.inner
+	}
+	wrapped := func(ps *State, node *Result) {
+		inner(ps, node)
+	}
+	if descriptions.parsers == nil {
+		descriptions.parsers = map[unsafe.Pointer]describedParser{}
+	}
+	descriptions.parsers[closureOf(wrapped)] = describedParser{desc: d, inner: inner}
+	return wrapped
+}
+
+// describeAs gives p the same description as parser, for wrappers like Map that don't change what is matched
+func describeAs(p Parser, parser Parser) Parser {
+	return describe(p, func() *description { return lookupDescription(parser) })
+}
+
+// describeNamed is called by NewParser
+func describeNamed(name string, p Parser, inner Parser) Parser {
+	return describe(p, func() *description {
+		d := &description{kind: namedKind, text: name}
+		if child := lookupDescription(inner); child.kind != unknownKind {
+			d.children = []*description{child}
+		}
+		return d
+	})
+}
+
+func lookupDescription(p Parser) *description {
+	if p != nil {
+		descriptions.Lock()
+		described, ok := descriptions.parsers[closureOf(p)]
+		descriptions.Unlock()
+		if ok {
+			return described.desc
+		}
+	}
+	return &description{kind: unknownKind}
+}
+
+func lookupDescriptions(parsers []Parser) []*description {
+	ret := make([]*description, len(parsers))
+	for i, p := range parsers {
+		ret[i] = lookupDescription(p)
+	}
+	return ret
+}
+
+func repeatDescription(kind descriptionKind, parser Parserish, separator []Parserish) func() *description {
+	return func() *description {
+		d := &description{kind: kind, children: []*description{lookupDescription(Parsify(parser))}}
+		if len(separator) > 0 {
+			d.children = append(d.children, lookupDescription(Parsify(separator[0])))
+		}
+		return d
+	}
+}
+
+func charsDescription(matcher string, negate, fold bool, repetition []int) func() *description {
+	return func() *description {
+		min, max := parseRepetition(1, -1, repetition...)
+		return &description{kind: charsKind, text: matcher, negate: negate, fold: fold, min: min, max: max}
+	}
+}
+
+// Grammar is a description of a parser and every rule it refers to, see Describe
+type Grammar struct {
+	rules []*rule
+	names map[*description]string
+}
+
+type rule struct {
+	name string
+	desc *description
+}
+
+// Describe turns a parser into a Grammar that can be written out as EBNF or a railroad diagram, to document the
+// language it parses. Only parsers built after RecordDescriptions was called can be described, anything else
+// shows up as <?>.
+//
+// Every *Parser, every parser named by NewParser and every Label becomes a rule of its own. Recursion is only
+// possible through a *Parser, which is shown as a reference to its rule, so recursive grammars describe fine.
+// Rules named with NewParser or Label keep their name, the rest are numbered.
+func Describe(parser Parserish) *Grammar {
+	g := &Grammar{names: map[*description]string{}}
+	root := resolve(lookupDescription(Parsify(parser)))
+
+	taken := map[string]bool{}
+	name := func(d *description, fallback string) {
+		base := fallback
+		if d.kind == namedKind {
+			base = d.text
+		}
+		n := base
+		for i := 2; taken[n]; i++ {
+			n = base + strconv.Itoa(i)
+		}
+		taken[n] = true
+		g.names[d] = n
+		g.rules = append(g.rules, &rule{name: n, desc: d})
+	}
+
+	name(root, "grammar")
+	// rules are added to the end as they are found, so this walks every one of them
+	for i := 0; i < len(g.rules); i++ {
+		var walk func(d *description, top bool)
+		walk = func(d *description, top bool) {
+			if d.kind == refKind {
+				d = resolve(d)
+				if _, ok := g.names[d]; !ok {
+					name(d, "rule"+strconv.Itoa(len(g.rules)))
+				}
+				return
+			}
+			if !top && d.kind == namedKind && len(d.children) > 0 {
+				if _, ok := g.names[d]; !ok {
+					name(d, "")
+				}
+				return
+			}
+			for _, child := range d.children {
+				walk(child, false)
+			}
+		}
+		walk(g.rules[i].desc, true)
+	}
+
+	return g
+}
+
+// resolve follows references to the description of what they point at
+func resolve(d *description) *description {
+	for seen := 0; d.kind == refKind && seen < 100; seen++ {
+		if *d.ref == nil {
+			return &description{kind: unknownKind}
+		}
+		d = lookupDescription(*d.ref)
+	}
+	return d
+}
+
+// body is what a rule is made of, without the name that made it a rule
+func (r *rule) body() *description {
+	if r.desc.kind == namedKind && len(r.desc.children) > 0 {
+		return r.desc.children[0]
+	}
+	return r.desc
+}
+
+// precedence of EBNF expressions, higher binds tighter
+const (
+	choicePrec = iota
+	seqPrec
+	postfixPrec
+)
+
+// EBNF writes the grammar out in W3C style EBNF, one rule per line:
+//  value ::= "null" | number | "[" (value ("," value)*)? "]"
+// Character classes are written like [a-z]+, regular expressions like /[a-z]+/, case insensitive literals like
+// "select"i and anything else by its name, eg <string literal>.
+func (g *Grammar) EBNF() string {
+	buf := &bytes.Buffer{}
+	for _, r := range g.rules {
+		buf.WriteString(r.name)
+		buf.WriteString(" ::= ")
+		g.expand(buf, r.body(), choicePrec)
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+func (g *Grammar) ebnf(buf *bytes.Buffer, d *description, prec int) {
+	if d.kind == refKind {
+		d = resolve(d)
+	}
+	if name, ok := g.names[d]; ok {
+		buf.WriteString(name)
+		return
+	}
+	g.expand(buf, d, prec)
+}
+
+// expand writes out what d is made of, even when it is a rule
+func (g *Grammar) expand(buf *bytes.Buffer, d *description, prec int) {
+	paren := func(inner int, f func()) {
+		if prec > inner {
+			buf.WriteByte('(')
+			defer buf.WriteByte(')')
+		}
+		f()
+	}
+
+	switch d.kind {
+	case emptyKind:
+		buf.WriteString(`""`)
+	case exactKind:
+		buf.WriteString(strconv.Quote(d.text))
+		if d.fold {
+			buf.WriteByte('i')
+		}
+	case charsKind:
+		buf.WriteByte('[')
+		if d.negate {
+			buf.WriteByte('^')
+		}
+		buf.WriteString(d.text)
+		buf.WriteByte(']')
+		buf.WriteString(repetition(d.min, d.max))
+	case regexKind:
+		buf.WriteString("/" + d.text + "/")
+	case seqKind:
+		var items []*description
+		for _, child := range d.children {
+			if child.kind != emptyKind {
+				items = append(items, child)
+			}
+		}
+		switch len(items) {
+		case 0:
+			buf.WriteString(`""`)
+		case 1:
+			g.ebnf(buf, items[0], prec)
+		default:
+			paren(seqPrec, func() {
+				for i, item := range items {
+					if i > 0 {
+						buf.WriteByte(' ')
+					}
+					g.ebnf(buf, item, seqPrec+1)
+				}
+			})
+		}
+	case anyKind:
+		paren(choicePrec, func() {
+			for i, child := range d.children {
+				if i > 0 {
+					buf.WriteString(" | ")
+				}
+				g.ebnf(buf, child, seqPrec)
+			}
+		})
+	case someKind, manyKind:
+		item := d.children[0]
+		if len(d.children) == 1 {
+			g.ebnf(buf, item, postfixPrec)
+			if d.kind == someKind {
+				buf.WriteByte('*')
+			} else {
+				buf.WriteByte('+')
+			}
+			return
+		}
+
+		list := func() {
+			g.ebnf(buf, item, seqPrec+1)
+			buf.WriteString(" (")
+			g.ebnf(buf, d.children[1], seqPrec+1)
+			buf.WriteByte(' ')
+			g.ebnf(buf, item, seqPrec+1)
+			buf.WriteString(")*")
+		}
+		if d.kind == someKind {
+			buf.WriteByte('(')
+			list()
+			buf.WriteString(")?")
+		} else {
+			paren(seqPrec, list)
+		}
+	case maybeKind:
+		g.ebnf(buf, d.children[0], postfixPrec)
+		buf.WriteByte('?')
+	case leftRecKind:
+		g.ebnf(buf, d.children[0], prec)
+	case notKind:
+		buf.WriteByte('!')
+		g.ebnf(buf, d.children[0], postfixPrec)
+	case peekKind:
+		buf.WriteByte('&')
+		g.ebnf(buf, d.children[0], postfixPrec)
+	case namedKind:
+		buf.WriteString("<" + d.text + ">")
+	default:
+		buf.WriteString("<?>")
+	}
+}
+
+func repetition(min, max int) string {
+	switch {
+	case min == 1 && max == -1:
+		return "+"
+	case min == 0 && max == -1:
+		return "*"
+	case min == 0 && max == 1:
+		return "?"
+	case min == 1 && max == 1:
+		return ""
+	case max == -1:
+		return fmt.Sprintf("{%d,}", min)
+	case min == max:
+		return fmt.Sprintf("{%d}", min)
+	}
+	return fmt.Sprintf("{%d,%d}", min, max)
+}
+
+// Rules lists the names of the rules in the grammar, starting with the one Describe was given
+func (g *Grammar) Rules() []string {
+	names := make([]string, len(g.rules))
+	for i, r := range g.rules {
+		names[i] = r.name
+	}
+	return names
+}
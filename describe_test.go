@@ -0,0 +1,88 @@
+package goparsify
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordDescriptions is RecordDescriptions for a single test, it returns a func that puts the setting back
+func recordDescriptions() (restore func()) {
+	was := atomic.SwapInt32(&descriptions.on, 1)
+	return func() {
+		atomic.StoreInt32(&descriptions.on, was)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	defer recordDescriptions()()
+
+	var value Parser
+	array := Seq("[", Cut(), Some(&value, ","), "]")
+	object := Seq("{", Cut(), Some(Seq(StringLit(`"`), ":", &value), ","), "}")
+	value = NewParser("value", Any(Bind("null", nil), NumberLit(), array, object))
+
+	t.Run("ebnf", func(t *testing.T) {
+		require.Equal(t, `value ::= "null" | <number literal> | "[" (value ("," value)*)? "]" | "{" ((<string literal> ":" value) ("," (<string literal> ":" value))*)? "}"
+`, Describe(&value).EBNF())
+	})
+
+	t.Run("parsers built before recording", func(t *testing.T) {
+		was := atomic.SwapInt32(&descriptions.on, 0)
+		early := Seq("a", "b")
+		atomic.StoreInt32(&descriptions.on, was)
+		require.Equal(t, "grammar ::= <?>\n", Describe(early).EBNF())
+	})
+
+	t.Run("unnamed recursion", func(t *testing.T) {
+		var parens, list Parser
+		parens = Seq("(", Many(&list), ")")
+		list = Some(&parens, Maybe(","))
+		require.Equal(t, `grammar ::= "(" rule1+ ")"
+rule1 ::= (grammar (","? grammar)*)?
+`, Describe(parens).EBNF())
+	})
+
+	t.Run("leaves", func(t *testing.T) {
+		g := Describe(Seq(
+			Label(Chars("a-z", 2, 4), "name"),
+			Regex("[0-9]+"),
+			NotChars(`"`, 0),
+			ExactFold("select"),
+			Keyword("if"),
+			Not(Chars("a-z", 1, 1)),
+			Peek(Any("x", Map(Exact("y"), func(n *Result) {}))),
+			Merge(NoAutoWS(Memo("m"))),
+			func(ps *State, node *Result) {},
+		))
+		require.Equal(t, []string{"grammar", "name"}, g.Rules())
+		require.Equal(t, `grammar ::= name /[0-9]+/ [^"]* "select"i "if" ![a-z] &("x" | "y") "m" <?>
+name ::= [a-z]{2,4}
+`, g.EBNF())
+	})
+
+	t.Run("duplicate names", func(t *testing.T) {
+		g := Describe(Seq(Label("a", "x"), Label("b", "x")))
+		require.Equal(t, "grammar ::= x x2\nx ::= \"a\"\nx2 ::= \"b\"\n", g.EBNF())
+	})
+
+	t.Run("svg", func(t *testing.T) {
+		svg := Describe(&value).SVG()
+		require.True(t, strings.HasPrefix(svg, "<svg "))
+		require.Contains(t, svg, `<text class="rule" x="10" y="20">value</text>`)
+		require.Contains(t, svg, `>&lt;string literal&gt;</text>`)
+
+		decoder := xml.NewDecoder(strings.NewReader(svg))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+		}
+	})
+}
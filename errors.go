@@ -1,26 +1,189 @@
 package goparsify
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
 
 // Error represents a parse error. These will often be set, the parser will back up a little and
 // find another viable path. In general when combining errors the longest error should be returned.
 type Error struct {
 	pos      int
 	expected string
+	input    string
+	// what a streaming State had discarded from the front of input when the error was raised
+	discarded *discarded
+	// every alternative that would have been accepted at pos, when known
+	alternatives []string
 }
 
 // Pos is the offset into the document the error was found
 func (e *Error) Pos() int { return e.pos }
 
+// Line is the 1 based line number the error was found on
+func (e *Error) Line() int { return lineOf(e.input, e.discarded, e.pos) }
+
+// Column is the 1 based column the error was found on, counted in runes
+func (e *Error) Column() int { return columnOf(e.input, e.discarded, e.pos) }
+
+// ByteColumn is the 1 based column the error was found on, counted in bytes
+func (e *Error) ByteColumn() int { return byteColumnOf(e.input, e.discarded, e.pos) }
+
+// LineText is the full text of the line the error was found on, without the line ending
+func (e *Error) LineText() string { return lineText(e.input, e.discarded, e.pos) }
+
+// Expected lists everything that would have been accepted at Pos
+func (e *Error) Expected() []string {
+	if len(e.alternatives) > 0 {
+		return e.alternatives
+	}
+	return []string{e.expected}
+}
+
 // Error satisfies the golang error interface
-func (e *Error) Error() string { return fmt.Sprintf("offset %d: expected %s", e.pos, e.expected) }
+func (e *Error) Error() string {
+	if len(e.alternatives) > 1 {
+		return formatError(e.input, e.discarded, e.pos, "expected one of: "+strings.Join(e.alternatives, ", "))
+	}
+	return formatError(e.input, e.discarded, e.pos, "expected "+e.expected)
+}
 
 // UnparsedInputError is returned by Run when not all of the input was consumed. There may still be a valid result
 type UnparsedInputError struct {
-	remaining string
+	pos       int
+	input     string
+	discarded *discarded
+}
+
+// Pos is the offset into the document where the unparsed input begins
+func (e UnparsedInputError) Pos() int { return e.pos }
+
+// Remaining is the input that was left unparsed
+func (e UnparsedInputError) Remaining() string {
+	return e.input[localOffset(e.input, e.discarded, e.pos):]
 }
 
+// Line is the 1 based line number the unparsed input begins on
+func (e UnparsedInputError) Line() int { return lineOf(e.input, e.discarded, e.pos) }
+
+// Column is the 1 based column the unparsed input begins on, counted in runes
+func (e UnparsedInputError) Column() int { return columnOf(e.input, e.discarded, e.pos) }
+
+// ByteColumn is the 1 based column the unparsed input begins on, counted in bytes
+func (e UnparsedInputError) ByteColumn() int { return byteColumnOf(e.input, e.discarded, e.pos) }
+
+// LineText is the full text of the line the unparsed input begins on, without the line ending
+func (e UnparsedInputError) LineText() string { return lineText(e.input, e.discarded, e.pos) }
+
 // Error satisfies the golang error interface
 func (e UnparsedInputError) Error() string {
-	return "left unparsed: " + e.remaining
+	return formatError(e.input, e.discarded, e.pos, "left unparsed")
+}
+
+// ErrorList is returned by Run when Recover skipped over errors. It has every error in the order they were found,
+// followed by the error that ended the parse if there was one.
+type ErrorList []error
+
+// Error satisfies the golang error interface
+func (l ErrorList) Error() string {
+	msgs := make([]string, len(l))
+	for i, err := range l {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors in the list
+func (l ErrorList) Unwrap() []error { return l }
+
+// discarded records what a streaming State has thrown away from the front of its input, so errors
+// raised afterwards can still report the right line and column.
+type discarded struct {
+	offset    int // bytes discarded
+	lines     int // newlines discarded
+	lineStart int // offset of the start of the line the remaining input begins on
+	lineRunes int // runes discarded since lineStart
+}
+
+// formatError renders a compiler style message, followed by the offending line and a caret pointing at pos:
+//  1:7: expected world
+//  hello there
+//        ^
+func formatError(input string, d *discarded, pos int, msg string) string {
+	local := localOffset(input, d, pos)
+	start := lineStart(input, local)
+
+	// keep tabs so the caret lines up with the text above it
+	caret := strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+		return ' '
+	}, input[start:local])
+
+	line, col := lineOf(input, d, pos), columnOf(input, d, pos)
+	return fmt.Sprintf("%d:%d: %s\n%s\n%s^", line, col, msg, lineText(input, d, pos), caret)
+}
+
+func clampOffset(input string, pos int) int {
+	if pos > len(input) {
+		return len(input)
+	}
+	if pos < 0 {
+		return 0
+	}
+	return pos
+}
+
+// localOffset turns a document offset into an index into input
+func localOffset(input string, d *discarded, pos int) int {
+	if d != nil {
+		pos -= d.offset
+	}
+	return clampOffset(input, pos)
+}
+
+func lineStart(input string, pos int) int {
+	pos = clampOffset(input, pos)
+	return strings.LastIndexByte(input[:pos], '\n') + 1
+}
+
+func lineOf(input string, d *discarded, pos int) int {
+	pos = localOffset(input, d, pos)
+	line := strings.Count(input[:pos], "\n") + 1
+	if d != nil {
+		line += d.lines
+	}
+	return line
+}
+
+func columnOf(input string, d *discarded, pos int) int {
+	pos = localOffset(input, d, pos)
+	start := lineStart(input, pos)
+	col := utf8.RuneCountInString(input[start:pos]) + 1
+	if start == 0 && d != nil {
+		col += d.lineRunes
+	}
+	return col
+}
+
+func byteColumnOf(input string, d *discarded, pos int) int {
+	pos = localOffset(input, d, pos)
+	start := lineStart(input, pos)
+	col := pos - start + 1
+	if start == 0 && d != nil {
+		col += d.offset - d.lineStart
+	}
+	return col
+}
+
+// lineText only has the part of the line that has not been discarded
+func lineText(input string, d *discarded, pos int) string {
+	pos = localOffset(input, d, pos)
+	line := input[lineStart(input, pos):]
+	if end := strings.IndexByte(line, '\n'); end != -1 {
+		line = line[:end]
+	}
+	return strings.TrimSuffix(line, "\r")
 }
@@ -0,0 +1,23 @@
+// +build !debug
+
+package goparsify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// the debug build records every parser call, so this only holds without it
+func TestErrorAllocs(t *testing.T) {
+	t.Run("no allocations while succeeding", func(t *testing.T) {
+		parser := Any("a", "b", "c")
+		ps := NewState("c")
+		allocs := testing.AllocsPerRun(100, func() {
+			ps.Pos = 0
+			parser(ps, TrashResult)
+		})
+		require.False(t, ps.Errored())
+		require.Equal(t, 0.0, allocs)
+	})
+}
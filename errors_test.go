@@ -0,0 +1,107 @@
+package goparsify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestError(t *testing.T) {
+	parser := Seq("key", "=", Chars("a-z"), ";")
+
+	t.Run("first line", func(t *testing.T) {
+		_, err := Run(parser, "key value;")
+		require.Equal(t, "1:5: expected '='\nkey value;\n    ^", err.Error())
+
+		perr := err.(*Error)
+		require.Equal(t, 4, perr.Pos())
+		require.Equal(t, 1, perr.Line())
+		require.Equal(t, 5, perr.Column())
+		require.Equal(t, 5, perr.ByteColumn())
+		require.Equal(t, "key value;", perr.LineText())
+	})
+
+	t.Run("later line", func(t *testing.T) {
+		_, err := Run(parser, "\r\n\nkey = 1;\r\nfoo")
+		require.Equal(t, "3:7: expected a-z\nkey = 1;\n      ^", err.Error())
+
+		perr := err.(*Error)
+		require.Equal(t, 9, perr.Pos())
+		require.Equal(t, 3, perr.Line())
+		require.Equal(t, 7, perr.Column())
+		require.Equal(t, "key = 1;", perr.LineText())
+	})
+
+	t.Run("runes and bytes", func(t *testing.T) {
+		_, err := Run(Seq("👺", "=", "goblin"), "👺 = troll")
+
+		perr := err.(*Error)
+		require.Equal(t, 1, perr.Line())
+		require.Equal(t, 5, perr.Column())
+		require.Equal(t, 8, perr.ByteColumn())
+	})
+
+	t.Run("tabs are kept under the caret", func(t *testing.T) {
+		_, err := Run(parser, "\tkey\t= 1")
+		require.Equal(t, "1:8: expected a-z\n\tkey\t= 1\n\t   \t  ^", err.Error())
+	})
+
+	t.Run("end of input", func(t *testing.T) {
+		_, err := Run(parser, "key\n= abc")
+
+		perr := err.(*Error)
+		require.Equal(t, 9, perr.Pos())
+		require.Equal(t, 2, perr.Line())
+		require.Equal(t, 6, perr.Column())
+		require.Equal(t, "= abc", perr.LineText())
+	})
+}
+
+func TestUnparsedInputError(t *testing.T) {
+	_, err := Run(Some("a"), "a a\n a b c")
+	require.Equal(t, "2:4: left unparsed\n a b c\n   ^", err.Error())
+
+	uerr := err.(UnparsedInputError)
+	require.Equal(t, 7, uerr.Pos())
+	require.Equal(t, "b c", uerr.Remaining())
+	require.Equal(t, 2, uerr.Line())
+	require.Equal(t, 4, uerr.Column())
+	require.Equal(t, 4, uerr.ByteColumn())
+	require.Equal(t, " a b c", uerr.LineText())
+}
+
+func TestExpected(t *testing.T) {
+	t.Run("any", func(t *testing.T) {
+		_, err := Run(Any("<", Chars("a-z"), StringLit(`"`)), "1")
+		require.Equal(t, "1:1: expected one of: '<', a-z, \"\n1\n^", err.Error())
+		require.Equal(t, []string{"'<'", "a-z", `"`}, err.(*Error).Expected())
+	})
+
+	t.Run("maybe and seq", func(t *testing.T) {
+		_, err := Run(Seq(Maybe("a"), "b"), "c")
+		require.Equal(t, []string{"'a'", "'b'"}, err.(*Error).Expected())
+	})
+
+	t.Run("some", func(t *testing.T) {
+		_, err := Run(Seq(Some("a", ","), "]"), "a,a x")
+		require.Equal(t, 4, err.(*Error).Pos())
+		require.Equal(t, []string{"','", "']'"}, err.(*Error).Expected())
+	})
+
+	t.Run("deduplicated", func(t *testing.T) {
+		_, err := Run(Any("a", "a", Seq(Maybe("a"), "b")), "c")
+		require.Equal(t, []string{"'a'", "'b'"}, err.(*Error).Expected())
+	})
+
+	t.Run("only the furthest", func(t *testing.T) {
+		_, err := Run(Any(Seq("a", "b"), "c"), "ax")
+		require.Equal(t, "1:2: expected 'b'\nax\n ^", err.Error())
+		require.Equal(t, []string{"'b'"}, err.(*Error).Expected())
+	})
+
+	t.Run("furthest even when the error is from somewhere else", func(t *testing.T) {
+		_, err := Run(Seq(Some(Seq("a", "b")), "c"), "ax")
+		require.Equal(t, "1:2: expected 'b'\nax\n ^", err.Error())
+		require.Equal(t, 1, err.(*Error).Pos())
+	})
+}
@@ -17,6 +17,10 @@ func ExampleCut() {
 	fmt.Println(err.Error())
 
 	// Output:
-	// left unparsed: <foo
-	// offset 9: expected >
+	// 1:6: left unparsed
+	// asdf <foo
+	//      ^
+	// 1:10: expected '>'
+	// asdf <foo
+	//          ^
 }
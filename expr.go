@@ -0,0 +1,212 @@
+package goparsify
+
+// Associativity decides how a chain of infix operators with the same precedence is grouped
+type Associativity int
+
+const (
+	// AssocLeft groups 1-2-3 as (1-2)-3
+	AssocLeft Associativity = iota
+	// AssocRight groups 2^3^4 as 2^(3^4)
+	AssocRight
+	// AssocNone does not allow chaining, a<b<c stops matching before the second <
+	AssocNone
+)
+
+type operatorKind int
+
+const (
+	prefixOperator operatorKind = iota
+	infixOperator
+	postfixOperator
+)
+
+// Operator is an entry in the operator table given to Expr, see Prefix, Infix and Postfix
+type Operator struct {
+	kind       operatorKind
+	assoc      Associativity
+	precedence int
+	op         Parser
+	build      func(n *Result)
+}
+
+// Prefix registers an operator that comes before its operand, eg -1 or !done. The build callback works like
+// Map, it is given a node with the operator in .Child[0] and the operand in .Child[1] and should set .Result.
+func Prefix(precedence int, op Parserish, build func(n *Result)) Operator {
+	return Operator{kind: prefixOperator, precedence: precedence, op: Parsify(op), build: build}
+}
+
+// Infix registers an operator that goes between its operands, eg 1+2. The build callback works like Map,
+// it is given a node with the left operand in .Child[0], the operator in .Child[1] and the right operand
+// in .Child[2] and should set .Result.
+func Infix(precedence int, assoc Associativity, op Parserish, build func(n *Result)) Operator {
+	return Operator{kind: infixOperator, assoc: assoc, precedence: precedence, op: Parsify(op), build: build}
+}
+
+// Postfix registers an operator that comes after its operand, eg i++ or 5!. The build callback works like
+// Map, it is given a node with the operand in .Child[0] and the operator in .Child[1] and should set .Result.
+func Postfix(precedence int, op Parserish, build func(n *Result)) Operator {
+	return Operator{kind: postfixOperator, precedence: precedence, op: Parsify(op), build: build}
+}
+
+// Expr builds an expression parser from an atom, eg a number or a bracketed expression, and a table of operators.
+// Operators with a higher precedence bind more tightly, and when several operators could match at the same
+// point they are tried in the order they were given. eg:
+//  var expr Parser
+//  atom := Any(NumberLit(), Seq("(", &expr, ")"))
+//  expr = Expr(atom,
+//      Infix(1, AssocLeft, "+", add),
+//      Infix(2, AssocLeft, "*", mul),
+//      Prefix(3, "-", negate),
+//  )
+// The parser uses precedence climbing instead of a parser per level, so deep tables stay cheap. An operator that
+// matches but is not followed by an operand is backtracked over, unless a Cut inside the operator was passed.
+func Expr(atom Parserish, operators ...Operator) Parser {
+	e := &expr{atom: Parsify(atom)}
+	for i, op := range operators {
+		if i == 0 || op.precedence < e.lowest {
+			e.lowest = op.precedence
+		}
+		if op.kind == prefixOperator {
+			e.prefix = append(e.prefix, op)
+		} else {
+			e.trailing = append(e.trailing, op)
+		}
+	}
+
+	return NewParser("Expr()", func(ps *State, node *Result) {
+		e.parse(ps, node, e.lowest)
+	})
+}
+
+type expr struct {
+	atom     Parser
+	lowest   int
+	prefix   []Operator
+	trailing []Operator
+}
+
+// parse matches an operand followed by any operators that bind at least as tightly as minPrecedence. Prefix
+// operands and right hand sides recurse through here, so it counts against MaxDepth like a *Parser does.
+func (e *expr) parse(ps *State, node *Result, minPrecedence int) {
+	if !ps.enter() {
+		return
+	}
+	e.climb(ps, node, minPrecedence)
+	ps.leave()
+}
+
+func (e *expr) climb(ps *State, node *Result, minPrecedence int) {
+	startpos, recovered := ps.Offset+ps.Pos, len(ps.Recovered)
+	e.operand(ps, node)
+	if ps.Errored() {
+		ps.rewind(startpos)
+		ps.forget(recovered)
+		return
+	}
+
+	nonAssoc := false
+	nonAssocPrecedence := 0
+
+next:
+	for {
+		for _, op := range e.trailing {
+			if op.precedence < minPrecedence {
+				continue
+			}
+			if nonAssoc && op.assoc == AssocNone && op.precedence == nonAssocPrecedence {
+				continue
+			}
+
+			oppos, oprecovered := ps.Offset+ps.Pos, len(ps.Recovered)
+			opNode := Result{}
+			op.op(ps, &opNode)
+			if ps.Errored() {
+				if ps.Cut > oppos {
+					ps.rewind(startpos)
+					ps.forget(recovered)
+					return
+				}
+				ps.Recover()
+				ps.rewind(oppos)
+				ps.forget(oprecovered)
+				continue
+			}
+			// every operator applied builds a Result
+			if !ps.step(1) {
+				ps.rewind(startpos)
+				ps.forget(recovered)
+				return
+			}
+
+			n := Result{}
+			if op.kind == postfixOperator {
+				n.Child = []Result{*node, opNode}
+			} else {
+				rhsPrecedence := op.precedence + 1
+				if op.assoc == AssocRight {
+					rhsPrecedence = op.precedence
+				}
+
+				rhs := Result{}
+				e.parse(ps, &rhs, rhsPrecedence)
+				if ps.Errored() {
+					if ps.Cut > oppos {
+						ps.rewind(startpos)
+						ps.forget(recovered)
+						return
+					}
+					ps.Recover()
+					ps.rewind(oppos)
+					ps.forget(oprecovered)
+					continue
+				}
+				n.Child = []Result{*node, opNode, rhs}
+				nonAssoc = op.assoc == AssocNone
+				nonAssocPrecedence = op.precedence
+			}
+
+			n.spanChildren(ps.Offset + ps.Pos)
+			op.build(&n)
+			*node = n
+			continue next
+		}
+		return
+	}
+}
+
+// operand matches an atom, or a prefix operator applied to an operand
+func (e *expr) operand(ps *State, node *Result) {
+	for _, op := range e.prefix {
+		oppos, recovered := ps.Offset+ps.Pos, len(ps.Recovered)
+		opNode := Result{}
+		op.op(ps, &opNode)
+		if ps.Errored() {
+			if ps.Cut > oppos {
+				return
+			}
+			ps.Recover()
+			ps.rewind(oppos)
+			ps.forget(recovered)
+			continue
+		}
+
+		operand := Result{}
+		e.parse(ps, &operand, op.precedence)
+		if ps.Errored() {
+			if ps.Cut > oppos {
+				return
+			}
+			ps.Recover()
+			ps.rewind(oppos)
+			ps.forget(recovered)
+			continue
+		}
+
+		*node = Result{Child: []Result{opNode, operand}}
+		node.spanChildren(ps.Offset + ps.Pos)
+		op.build(node)
+		return
+	}
+
+	e.atom(ps, node)
+}
@@ -0,0 +1,127 @@
+package goparsify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sexpr renders the operator tree so the grouping is easy to assert on
+func sexpr(n *Result) {
+	parts := []interface{}{}
+	for _, child := range n.Child {
+		if child.Result != nil {
+			parts = append(parts, child.Result)
+		} else {
+			parts = append(parts, child.Token)
+		}
+	}
+	switch len(parts) {
+	case 2:
+		n.Result = fmt.Sprintf("(%v %v)", parts[0], parts[1])
+	case 3:
+		n.Result = fmt.Sprintf("(%v %v %v)", parts[1], parts[0], parts[2])
+	}
+}
+
+func TestExpr(t *testing.T) {
+	var expr Parser
+	atom := Any(
+		Chars("a-z0-9").Map(func(n *Result) { n.Result = n.Token }),
+		Seq("(", &expr, ")").Map(func(n *Result) { n.Result = n.Child[1].Result }),
+	)
+	expr = Expr(atom,
+		Infix(1, AssocNone, "==", sexpr),
+		Infix(2, AssocLeft, "+", sexpr),
+		Infix(2, AssocLeft, "-", sexpr),
+		Infix(3, AssocLeft, "*", sexpr),
+		Prefix(4, "-", sexpr),
+		Infix(5, AssocRight, "^", sexpr),
+		Postfix(6, "!", sexpr),
+		Infix(7, AssocLeft, ".", sexpr),
+	)
+
+	tests := map[string]string{
+		"a":              "a",
+		"a + b":          "(+ a b)",
+		"a + b + c":      "(+ (+ a b) c)",
+		"a - b + c":      "(+ (- a b) c)",
+		"a + b * c":      "(+ a (* b c))",
+		"a * b + c":      "(+ (* a b) c)",
+		"(a + b) * c":    "(* (+ a b) c)",
+		"a ^ b ^ c":      "(^ a (^ b c))",
+		"-a ^ b":         "(- (^ a b))",
+		"--a":            "(- (- a))",
+		"a - -b":         "(- a (- b))",
+		"a!":             "(a !)",
+		"a.b!":           "((. a b) !)",
+		"-a!":            "(- (a !))",
+		"a + b == c * d": "(== (+ a b) (* c d))",
+	}
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			result, err := Run(expr, input)
+			require.NoError(t, err)
+			require.Equal(t, expected, result)
+		})
+	}
+
+	t.Run("non associative", func(t *testing.T) {
+		node, ps := runParser("a == b == c", expr)
+		require.False(t, ps.Errored())
+		require.Equal(t, "(== a b)", node.Result)
+		require.Equal(t, " == c", ps.Get())
+	})
+
+	t.Run("backtracks over dangling operators", func(t *testing.T) {
+		node, ps := runParser("a + b *", expr)
+		require.False(t, ps.Errored())
+		require.Equal(t, "(+ a b)", node.Result)
+		require.Equal(t, " *", ps.Get())
+	})
+
+	t.Run("spans", func(t *testing.T) {
+		node, _ := runParser(" a + bc", expr)
+		require.Equal(t, 1, node.Start)
+		require.Equal(t, 7, node.End)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, ps := runParser("+", expr)
+		require.True(t, ps.Errored())
+		require.Equal(t, 0, ps.Error.Pos())
+		require.Equal(t, 0, ps.Pos)
+	})
+
+	t.Run("cut", func(t *testing.T) {
+		cut := Expr(atom, Infix(1, AssocLeft, Seq("+", Cut()), sexpr))
+
+		_, ps := runParser("a + )", cut)
+		require.True(t, ps.Errored())
+		require.Equal(t, 4, ps.Error.Pos())
+		require.Equal(t, 0, ps.Pos)
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		negated := Expr(NumberLit(), Prefix(1, "-", sexpr))
+		input := strings.Repeat("-", 200000) + "1"
+
+		ps := NewState(input)
+		ps.MaxDepth = 100
+		_, err := RunState(negated, ps)
+		require.IsType(t, &DepthLimitError{}, err)
+
+		ps = NewState(input)
+		ps.MaxSteps = 1000
+		_, err = RunState(negated, ps)
+		require.IsType(t, &StepLimitError{}, err)
+
+		factorial := Expr(NumberLit(), Postfix(1, "!", sexpr))
+		ps = NewState("1" + strings.Repeat("!", 2000))
+		ps.MaxSteps = 1000
+		_, err = RunState(factorial, ps)
+		require.IsType(t, &StepLimitError{}, err)
+	})
+}
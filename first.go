@@ -0,0 +1,273 @@
+package goparsify
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// byteSet has a bit for each byte
+type byteSet [4]uint64
+
+func (s *byteSet) add(b byte) {
+	s[b>>6] |= 1 << (b & 63)
+}
+
+func (s *byteSet) addRange(from, to rune) {
+	for b := from; b <= to && b <= 0xff; b++ {
+		s.add(byte(b))
+	}
+}
+
+func (s *byteSet) addAll() {
+	*s = byteSet{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}
+}
+
+func (s *byteSet) has(b byte) bool {
+	return s[b>>6]&(1<<(b&63)) != 0
+}
+
+func (s *byteSet) union(other byteSet) {
+	for i := range s {
+		s[i] |= other[i]
+	}
+}
+
+// firstSet is what a parser can start with, after whitespace. A nullable parser can match without consuming
+// anything, so it has to be tried whatever comes next.
+type firstSet struct {
+	bytes    byteSet
+	nullable bool
+}
+
+func unknownFirst() firstSet {
+	f := firstSet{nullable: true}
+	f.bytes.addAll()
+	return f
+}
+
+// firstOf works out what d can start with from its description, erring towards too much when it can't tell
+func firstOf(d *description, visiting map[*description]bool) firstSet {
+	d = resolve(d)
+	// coming back around before consuming anything is left recursion, which only LeftRec can make sense of
+	if visiting[d] {
+		return unknownFirst()
+	}
+	visiting[d] = true
+	defer delete(visiting, d)
+
+	var f firstSet
+	switch d.kind {
+	case emptyKind, notKind, peekKind:
+		f.nullable = true
+	case exactKind:
+		if d.text == "" {
+			f.nullable = true
+			break
+		}
+		r, _ := utf8.DecodeRuneInString(d.text)
+		addRune(&f.bytes, r)
+		if d.fold {
+			addFold(&f.bytes, r)
+		}
+	case charsKind:
+		f = charsFirst(d)
+	case seqKind:
+		f.nullable = true
+		for _, child := range d.children {
+			cf := firstOf(child, visiting)
+			f.bytes.union(cf.bytes)
+			if !cf.nullable {
+				f.nullable = false
+				break
+			}
+		}
+	case anyKind:
+		for _, child := range d.children {
+			cf := firstOf(child, visiting)
+			f.bytes.union(cf.bytes)
+			f.nullable = f.nullable || cf.nullable
+		}
+	case someKind, maybeKind:
+		f = firstOf(d.children[0], visiting)
+		f.nullable = true
+	case manyKind, leftRecKind:
+		f = firstOf(d.children[0], visiting)
+	case namedKind:
+		switch {
+		case d.first != "":
+			for i := 0; i < len(d.first); i++ {
+				f.bytes.add(d.first[i])
+			}
+		case len(d.children) > 0:
+			f = firstOf(d.children[0], visiting)
+		default:
+			f = unknownFirst()
+		}
+	default:
+		f = unknownFirst()
+	}
+	return f
+}
+
+func charsFirst(d *description) firstSet {
+	var f firstSet
+	f.nullable = d.min == 0
+
+	var matched byteSet
+	add := func(r rune) {
+		addRune(&matched, r)
+		if d.fold {
+			addFold(&matched, r)
+		}
+	}
+	alphabet, ranges := parseMatcher(d.text)
+	for _, r := range alphabet {
+		add(r)
+	}
+	for _, r := range ranges {
+		for c := r[0]; c <= r[1] && c < utf8.RuneSelf; c++ {
+			add(c)
+		}
+		if r[1] >= utf8.RuneSelf {
+			add(r[1])
+		}
+	}
+	if d.fold {
+		// runes in a range are only folded up to the first multi byte one, so look for ascii letters that fold into
+		// the rest of it, eg ſ which folds to s
+		for b := rune(0); b < utf8.RuneSelf; b++ {
+			for f := unicode.SimpleFold(b); f != b; f = unicode.SimpleFold(f) {
+				if f >= utf8.RuneSelf && inMatcher(f, alphabet, ranges) {
+					matched.add(byte(b))
+				}
+			}
+		}
+	}
+
+	if !d.negate {
+		f.bytes = matched
+		return f
+	}
+	// anything outside of the matcher is accepted, including every multi byte rune
+	f.bytes.addAll()
+	for b := 0; b < utf8.RuneSelf; b++ {
+		if matched.has(byte(b)) {
+			f.bytes[b>>6] &^= 1 << uint(b&63)
+		}
+	}
+	return f
+}
+
+func addRune(s *byteSet, r rune) {
+	if r >= utf8.RuneSelf {
+		s.addRange(utf8.RuneSelf, 0xff)
+		return
+	}
+	s.add(byte(r))
+}
+
+// addFold adds everything r folds to under unicode simple case folding, eg k folds to K and the kelvin sign
+func addFold(s *byteSet, r rune) {
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		addRune(s, f)
+	}
+}
+
+func inMatcher(r rune, alphabet string, ranges [][]rune) bool {
+	for _, c := range alphabet {
+		if c == r {
+			return true
+		}
+	}
+	for _, rng := range ranges {
+		if rng[0] <= r && r <= rng[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// expectedOf is what a parser expects when it fails where it starts, in the order it would say so. It is false when
+// the description doesn't tell, for parsers that can't be skipped.
+func expectedOf(d *description, visiting map[*description]bool) ([]string, bool) {
+	d = resolve(d)
+	if visiting[d] {
+		return nil, false
+	}
+	visiting[d] = true
+	defer delete(visiting, d)
+
+	if d.expected != nil {
+		return d.expected, true
+	}
+
+	switch d.kind {
+	case emptyKind:
+		return nil, true
+	case exactKind:
+		if d.text == "" {
+			return nil, true
+		}
+		return []string{"'" + d.text + "'"}, true
+	case charsKind:
+		if d.min == 0 {
+			return nil, true
+		}
+		return []string{d.text}, true
+	case seqKind, anyKind:
+		var expected []string
+		for _, child := range d.children {
+			e, ok := expectedOf(child, visiting)
+			if !ok {
+				return nil, false
+			}
+			expected = append(expected, e...)
+			if d.kind == seqKind && !firstOf(child, map[*description]bool{}).nullable {
+				break
+			}
+		}
+		return expected, true
+	case someKind, manyKind, maybeKind:
+		return expectedOf(d.children[0], visiting)
+	case namedKind:
+		if d.first == "" && len(d.children) > 0 {
+			return expectedOf(d.children[0], visiting)
+		}
+	}
+	return nil, false
+}
+
+// dispatchTable has a bit for each alternative of an Any that could match input starting with that byte
+type dispatchTable struct {
+	candidates [256]uint64
+	// expected is what each alternative expects, which is all it could have said when it is skipped
+	expected [][]string
+}
+
+// newDispatchTable returns nil when every alternative has to be tried whatever the input is, or there are
+// too many of them to fit
+func newDispatchTable(parsers []Parser) *dispatchTable {
+	if len(parsers) > 64 {
+		return nil
+	}
+
+	table := &dispatchTable{expected: make([][]string, len(parsers))}
+	useful := false
+	for i, p := range parsers {
+		d := lookupDescription(p)
+		f := firstOf(d, map[*description]bool{})
+		expected, ok := expectedOf(d, map[*description]bool{})
+		table.expected[i] = expected
+		for b := range table.candidates {
+			if f.nullable || f.bytes.has(byte(b)) || !ok || len(expected) == 0 {
+				table.candidates[b] |= 1 << uint(i)
+			} else {
+				useful = true
+			}
+		}
+	}
+	if !useful {
+		return nil
+	}
+	return table
+}
@@ -0,0 +1,177 @@
+package goparsify
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func firstBytes(p Parserish) (string, bool) {
+	f := firstOf(lookupDescription(Parsify(p)), map[*description]bool{})
+	var bytes []byte
+	for b := 0; b < 256; b++ {
+		if f.bytes.has(byte(b)) {
+			bytes = append(bytes, byte(b))
+		}
+	}
+	return string(bytes), f.nullable
+}
+
+func TestFirstOf(t *testing.T) {
+	defer recordDescriptions()()
+
+	t.Run("literals", func(t *testing.T) {
+		first, nullable := firstBytes("null")
+		require.Equal(t, "n", first)
+		require.False(t, nullable)
+
+		first, _ = firstBytes(StringLit(`"'`))
+		require.Equal(t, `"'`, first)
+
+		first, _ = firstBytes(NumberLit())
+		require.Equal(t, "+-.0123456789", first)
+	})
+
+	t.Run("chars", func(t *testing.T) {
+		first, nullable := firstBytes(Chars("a-c_"))
+		require.Equal(t, "_abc", first)
+		require.False(t, nullable)
+
+		_, nullable = firstBytes(Chars("a-c", 0))
+		require.True(t, nullable)
+
+		first, _ = firstBytes(Chars("é"))
+		require.Len(t, first, 128)
+	})
+
+	t.Run("folding includes the other case and runes that fold to ascii", func(t *testing.T) {
+		first, _ := firstBytes(ExactFold("k"))
+		require.Equal(t, "Kk", first[:2])
+		require.Len(t, first, 130)
+
+		first, _ = firstBytes(CharsFold("a"))
+		require.Equal(t, "Aa", first[:2])
+	})
+
+	t.Run("folding follows unicode simple case folding", func(t *testing.T) {
+		for _, p := range []Parser{CharsFold("ſ"), CharsFold("ā-ƀ"), ExactFold("ſelect")} {
+			first, _ := firstBytes(p)
+			require.Contains(t, first, "S")
+			require.Contains(t, first, "s")
+		}
+	})
+
+	t.Run("not chars", func(t *testing.T) {
+		first, _ := firstBytes(NotChars("a-z"))
+		require.Len(t, first, 256-26)
+		require.NotContains(t, first, "q")
+	})
+
+	t.Run("sequences look past things that can match nothing", func(t *testing.T) {
+		first, nullable := firstBytes(Seq(Maybe("-"), Cut(), Chars("0-9"), "x"))
+		require.Equal(t, "-0123456789", first)
+		require.False(t, nullable)
+
+		_, nullable = firstBytes(Seq(Maybe("-"), Some("x")))
+		require.True(t, nullable)
+	})
+
+	t.Run("choices combine", func(t *testing.T) {
+		first, nullable := firstBytes(Any("b", Many("a"), Not("c")))
+		require.Equal(t, "ab", first)
+		require.True(t, nullable)
+	})
+
+	t.Run("unknown parsers can start with anything", func(t *testing.T) {
+		first, nullable := firstBytes(Regex("[a-z]"))
+		require.Len(t, first, 256)
+		require.True(t, nullable)
+	})
+
+	t.Run("left recursion can start with anything", func(t *testing.T) {
+		var expr Parser
+		expr = Any(Seq(&expr, "+", "1"), "1")
+		first, _ := firstBytes(&expr)
+		require.Len(t, first, 256)
+	})
+}
+
+func TestDispatchTable(t *testing.T) {
+	defer recordDescriptions()()
+
+	t.Run("picks out candidates", func(t *testing.T) {
+		table := newDispatchTable([]Parser{Exact("a"), Chars("a-z"), Maybe("x")})
+		require.NotNil(t, table)
+		require.Equal(t, uint64(0x7), table.candidates['a'])
+		require.Equal(t, uint64(0x6), table.candidates['q'])
+		require.Equal(t, uint64(0x4), table.candidates['1'])
+	})
+
+	t.Run("is skipped when every alternative has to be tried", func(t *testing.T) {
+		require.Nil(t, newDispatchTable([]Parser{Regex("a"), Maybe("b")}))
+	})
+
+	t.Run("matches the same as trying every alternative", func(t *testing.T) {
+		// plain funcs have no description, so an Any of them tries every alternative in order
+		undispatched := func(parsers ...Parser) Parser {
+			var plain []Parserish
+			for _, p := range parsers {
+				p := p
+				plain = append(plain, func(ps *State, node *Result) { p(ps, node) })
+			}
+			return Any(plain...)
+		}
+		alternatives := []Parser{
+			CharsFold("ſ"),
+			ExactFold("ſelect"),
+			CharsFold("ā-ƀ"),
+			ExactFold("k"),
+			Chars("a-z").Map(func(n *Result) { n.Result = "word" }),
+			Seq(Maybe("-"), Chars("0-9")),
+			StringLit(`"`),
+			Label(Seq("<", Chars("a-z")), "tag"),
+			Seq(Some("+"), Maybe(Keyword("in")), OneOf("=", "==", "!=")),
+			Any(Ident("A-Z", "a-z"), NumberLit()),
+		}
+		var alternativesish []Parserish
+		for _, p := range alternatives {
+			alternativesish = append(alternativesish, p)
+		}
+		dispatched := Any(alternativesish...)
+		ordered := undispatched(alternatives...)
+
+		for _, input := range []string{"s", "S", "ſ", "select", "SELECT", "ſelect", "ŝ", "K", "\u212a", "x", "-1", "1",
+			`"a"`, "<a", "<", "+=", "in!=", "++?", "Ab", ".5", "?", ""} {
+			want, wantErr := Run(ordered, input, NoWhitespace)
+			got, gotErr := Run(dispatched, input, NoWhitespace)
+			require.Equal(t, want, got, input)
+			require.Equal(t, wantErr, gotErr, input)
+		}
+	})
+	t.Run("doesn't run the alternatives it skips when nothing matches", func(t *testing.T) {
+		calls := 0
+		counted := func(p Parserish) Parser {
+			parser := Parsify(p)
+			return describeAs(func(ps *State, node *Result) {
+				calls++
+				parser(ps, node)
+			}, parser)
+		}
+
+		_, err := Run(Any(counted("null"), counted(NumberLit()), counted(Seq("[", "]"))), "?")
+		require.Equal(t, []string{"'null'", "number", "'['"}, err.(*Error).Expected())
+		require.Equal(t, 0, calls)
+	})
+
+	t.Run("more alternatives than fit are all tried", func(t *testing.T) {
+		var alternatives []Parserish
+		for i := 0; i < 70; i++ {
+			alternatives = append(alternatives, fmt.Sprintf("x%d;", i))
+		}
+
+		result, err := Run(Any(alternatives...), "x69;")
+		require.NoError(t, err)
+		require.Nil(t, result)
+	})
+}
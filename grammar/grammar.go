@@ -0,0 +1,418 @@
+// Package grammar builds parsers at runtime from a PEG grammar written as text, eg:
+//  # comments start with # or //
+//  filter     <- comparison (("and"i / "or"i) comparison)*
+//  comparison <- field ("=" / "!=") value
+//  field      <- [a-z_]+
+//  value      <- '"' [^"]* '"' / [0-9]+
+//
+// Rules are defined with <-, = or ::=, and may end with a ;. Expressions are made of:
+//  - "literals" or 'literals', with an i after the quote to ignore case: Exact and ExactFold
+//  - [a-z] classes of characters in the same format as Chars, [^a-z] for anything else: Chars and NotChars
+//  - . for any character
+//  - rule names, which can refer to rules defined later on
+//  - (groups)
+//  - a b for a sequence: Seq
+//  - a / b or a | b for the first that matches: Any
+//  - a* a+ and a? for zero or more, one or more and maybe: Some, Many and Maybe
+//  - !a and &a to look ahead without matching: Not and Peek
+//
+// Whitespace is skipped between tokens by whatever is passed to Run, just like hand written parsers.
+package grammar
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	. "github.com/vektah/goparsify"
+)
+
+// DefinitionError is a mistake in a grammar that parsed fine, like a reference to a rule that doesn't exist.
+// Line and Col are 0 when the mistake isn't in the definition, like an action for a rule that doesn't exist.
+type DefinitionError struct {
+	Line    int
+	Col     int
+	Message string
+}
+
+func (e *DefinitionError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Col, e.Message)
+}
+
+type termKind int
+
+const (
+	literalTerm termKind = iota
+	classTerm
+	dotTerm
+	refTerm
+	seqTerm
+	anyTerm
+	someTerm
+	manyTerm
+	maybeTerm
+	notTerm
+	peekTerm
+)
+
+// term is a piece of the grammar, it is only turned into a parser once every rule is known
+type term struct {
+	kind termKind
+	pos  int
+	// text is the literal, the characters in a class or the name of a rule
+	text     string
+	fold     bool
+	negate   bool
+	min, max int
+	children []*term
+}
+
+type rule struct {
+	name  string
+	start int
+	expr  *term
+}
+
+type builder struct {
+	definition string
+	rules      map[string]*Parser
+	errors     ErrorList
+}
+
+func (b *builder) errorAt(pos int, format string, args ...interface{}) {
+	line, col := LineCol(b.definition, pos)
+	b.errors = append(b.errors, &DefinitionError{Line: line, Col: col, Message: fmt.Sprintf(format, args...)})
+}
+
+var (
+	_expr   Parser
+	_name   = Ident("a-zA-Z_", "a-zA-Z0-9_")
+	_define = Any("<-", "::=", "=")
+
+	_literal = Seq(StringLit(`"'`), NoAutoWS(Maybe(Keyword("i")))).Map(func(n *Result) {
+		n.Result = &term{kind: literalTerm, pos: n.Start, text: n.Child[0].Token, fold: n.Child[1].Token != ""}
+	})
+
+	_class = Label(Regex(`\[\^?(\\.|[^\]\\])*\]`), "character class").Map(func(n *Result) {
+		body := n.Token[1 : len(n.Token)-1]
+		negate := strings.HasPrefix(body, "^")
+		body = strings.TrimPrefix(body, "^")
+		body = strings.NewReplacer(`\n`, "\n", `\r`, "\r", `\t`, "\t").Replace(body)
+		n.Result = &term{kind: classTerm, pos: n.Start, text: body, negate: negate, min: 1, max: 1}
+	})
+
+	_dot = Map(".", func(n *Result) {
+		n.Result = &term{kind: dotTerm, pos: n.Start}
+	})
+
+	_group = Seq("(", &_expr, ")").Map(func(n *Result) {
+		n.Result = n.Child[1].Result
+	})
+
+	// a rule name followed by <- is the start of the next rule, not a reference
+	_ref = Seq(_name, Not(_define)).Map(func(n *Result) {
+		n.Result = &term{kind: refTerm, pos: n.Child[0].Start, text: n.Child[0].Token}
+	})
+
+	_primary = Any(_literal, _class, _dot, _group, _ref)
+
+	_suffix = Seq(_primary, NoAutoWS(Maybe(Chars("*+?", 1, 1)))).Map(func(n *Result) {
+		n.Result = repeat(n.Child[0].Result.(*term), n.Child[1].Token, n.Child[0].Start)
+	})
+
+	_prefix = Seq(Maybe(Chars("!&", 1, 1)), _suffix).Map(func(n *Result) {
+		expr := n.Child[1].Result.(*term)
+		switch n.Child[0].Token {
+		case "!":
+			expr = &term{kind: notTerm, pos: n.Start, children: []*term{expr}}
+		case "&":
+			expr = &term{kind: peekTerm, pos: n.Start, children: []*term{expr}}
+		}
+		n.Result = expr
+	})
+
+	_sequence = Many(_prefix).Map(func(n *Result) {
+		n.Result = combine(n.Child, seqTerm)
+	})
+
+	_rule = Seq(_name, _define, Cut(), &_expr, Maybe(";")).Map(func(n *Result) {
+		n.Result = rule{name: n.Child[0].Token, start: n.Child[0].Start, expr: n.Child[3].Result.(*term)}
+	})
+
+	_grammar = Many(_rule).Map(func(n *Result) {
+		rules := make([]rule, len(n.Child))
+		for i, child := range n.Child {
+			rules[i] = child.Result.(rule)
+		}
+		n.Result = rules
+	})
+
+	_ws = WhitespaceWith("#", "//")
+)
+
+func init() {
+	_expr = Many(_sequence, Any("/", "|")).Map(func(n *Result) {
+		n.Result = combine(n.Child, anyTerm)
+	})
+}
+
+// repeat applies a *, + or ? suffix. A single character class becomes a class with a count, so it can use one Chars.
+func repeat(expr *term, op string, pos int) *term {
+	if op == "" {
+		return expr
+	}
+
+	if expr.kind == classTerm && expr.min == 1 && expr.max == 1 {
+		counted := *expr
+		switch op {
+		case "*":
+			counted.min, counted.max = 0, -1
+		case "+":
+			counted.min, counted.max = 1, -1
+		case "?":
+			counted.min, counted.max = 0, 1
+		}
+		return &counted
+	}
+
+	kind := maybeTerm
+	switch op {
+	case "*":
+		kind = someTerm
+	case "+":
+		kind = manyTerm
+	}
+	return &term{kind: kind, pos: pos, children: []*term{expr}}
+}
+
+// combine joins the children into a sequence or alternatives, leaving a single child as it is
+func combine(children []Result, kind termKind) *term {
+	if len(children) == 1 {
+		return children[0].Result.(*term)
+	}
+
+	t := &term{kind: kind, pos: children[0].Start}
+	for _, child := range children {
+		t.children = append(t.children, child.Result.(*term))
+	}
+	return t
+}
+
+// compile turns a term into a parser
+func (b *builder) compile(t *term) Parser {
+	switch t.kind {
+	case literalTerm:
+		if t.fold {
+			return ExactFold(t.text)
+		}
+		return Exact(t.text)
+	case classTerm:
+		if t.negate {
+			return NotChars(t.text, t.min, t.max)
+		}
+		return Chars(t.text, t.min, t.max)
+	case dotTerm:
+		return Label(NotChars("", 1, 1), "any character")
+	case refTerm:
+		rule, ok := b.rules[t.text]
+		if !ok {
+			b.errorAt(t.pos, "undefined rule '%s'", t.text)
+			return nil
+		}
+		return Parsify(rule)
+	}
+
+	children := make([]Parserish, len(t.children))
+	for i, child := range t.children {
+		children[i] = b.compile(child)
+	}
+	switch t.kind {
+	case seqTerm:
+		return Seq(children...)
+	case anyTerm:
+		return Any(children...)
+	case someTerm:
+		return Some(children[0])
+	case manyTerm:
+		return Many(children[0])
+	case maybeTerm:
+		return Maybe(children[0])
+	case notTerm:
+		return Not(children[0])
+	default:
+		return Peek(children[0])
+	}
+}
+
+// nullable is whether a term can match without consuming any input, given the rules that can
+func nullable(t *term, rules map[string]bool) bool {
+	switch t.kind {
+	case literalTerm:
+		return t.text == ""
+	case classTerm:
+		return t.min == 0
+	case dotTerm:
+		return false
+	case refTerm:
+		return rules[t.text]
+	case seqTerm:
+		for _, child := range t.children {
+			if !nullable(child, rules) {
+				return false
+			}
+		}
+		return true
+	case anyTerm, manyTerm:
+		for _, child := range t.children {
+			if nullable(child, rules) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+// leading calls visit with every rule reference a term may follow before it has consumed any input
+func leading(t *term, rules map[string]bool, visit func(ref *term)) {
+	if t.kind == refTerm {
+		visit(t)
+		return
+	}
+	for _, child := range t.children {
+		leading(child, rules, visit)
+		if t.kind == seqTerm && !nullable(child, rules) {
+			return
+		}
+	}
+}
+
+// check finds the rules that would never finish: those repeating something that can match nothing, and those that
+// call themselves again before consuming any input
+func (b *builder) check(rules []rule) {
+	nullableRules := map[string]bool{}
+	for changed := true; changed; {
+		changed = false
+		for _, r := range rules {
+			if !nullableRules[r.name] && nullable(r.expr, nullableRules) {
+				nullableRules[r.name], changed = true, true
+			}
+		}
+	}
+
+	calls := map[string][]*term{}
+	for _, r := range rules {
+		b.checkRepetition(r.expr, nullableRules)
+		name := r.name
+		leading(r.expr, nullableRules, func(ref *term) {
+			calls[name] = append(calls[name], ref)
+		})
+	}
+
+	for _, r := range rules {
+		if path := leftRecursion(r.name, calls); path != nil {
+			names := []string{r.name}
+			for _, ref := range path {
+				names = append(names, ref.text)
+			}
+			b.errorAt(path[0].pos, "rule '%s' is left recursive: %s", r.name, strings.Join(names, " -> "))
+		}
+	}
+}
+
+func (b *builder) checkRepetition(t *term, nullableRules map[string]bool) {
+	if (t.kind == someTerm || t.kind == manyTerm) && nullable(t.children[0], nullableRules) {
+		b.errorAt(t.pos, "repetition of something that can match nothing")
+	}
+	for _, child := range t.children {
+		b.checkRepetition(child, nullableRules)
+	}
+}
+
+// leftRecursion finds the references a rule can follow back to itself without consuming any input, if there are any
+func leftRecursion(name string, calls map[string][]*term) []*term {
+	seen := map[string]bool{}
+	var path []*term
+	var find func(from string) bool
+	find = func(from string) bool {
+		for _, ref := range calls[from] {
+			path = append(path, ref)
+			if ref.text == name {
+				return true
+			}
+			if !seen[ref.text] {
+				seen[ref.text] = true
+				if find(ref.text) {
+					return true
+				}
+			}
+			path = path[:len(path)-1]
+		}
+		return false
+	}
+
+	if find(name) {
+		return path
+	}
+	return nil
+}
+
+// Load parses a grammar definition into a parser for each of its rules. The actions are attached to the rules
+// with the same name, like Map, to build their .Result.
+//
+// Syntax errors in the definition are returned the same way as Run returns them. References to rules that don't
+// exist, rules that are defined twice, rules that would never finish and actions for rules that don't exist are all
+// returned together in an ErrorList of *DefinitionError. A rule never finishes if it repeats something that can match
+// nothing, like ("a"?)*, or if it is left recursive, calling itself again before consuming any input, like
+// expr <- expr "+" num. Left recursion can usually be written as a repetition instead, expr <- num ("+" num)*.
+func Load(definition string, actions map[string]func(n *Result)) (map[string]Parser, error) {
+	result, err := Run(_grammar, definition, _ws)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &builder{definition: definition, rules: map[string]*Parser{}}
+	var rules []rule
+	for _, r := range result.([]rule) {
+		if _, ok := b.rules[r.name]; ok {
+			b.errorAt(r.start, "rule '%s' is defined more than once", r.name)
+			continue
+		}
+		b.rules[r.name] = new(Parser)
+		rules = append(rules, r)
+	}
+
+	for _, r := range rules {
+		p := NewParser(r.name, b.compile(r.expr))
+		if action, ok := actions[r.name]; ok {
+			p = p.Map(action)
+		}
+		*b.rules[r.name] = p
+	}
+	b.check(rules)
+
+	var unused []string
+	for name := range actions {
+		if _, ok := b.rules[name]; !ok {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	for _, name := range unused {
+		b.errors = append(b.errors, &DefinitionError{Message: fmt.Sprintf("action for undefined rule '%s'", name)})
+	}
+
+	if len(b.errors) > 0 {
+		return nil, b.errors
+	}
+
+	parsers := map[string]Parser{}
+	for name, p := range b.rules {
+		parsers[name] = *p
+	}
+	return parsers, nil
+}
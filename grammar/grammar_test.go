@@ -0,0 +1,154 @@
+package grammar
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/goparsify"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("filter language", func(t *testing.T) {
+		parsers, err := Load(`
+			# a tiny filter language
+			filter     <- comparison (("and"i / "or"i) comparison)*
+			comparison <- field ("=" | "!=") value;
+			field      =  [a-z_]+
+			value      ::= '"' [^"]* '"' / [0-9]+
+		`, nil)
+		require.NoError(t, err)
+
+		_, err = goparsify.Run(parsers["filter"], `name = "bob" AND age != 3`, goparsify.ASCIIWhitespace)
+		require.NoError(t, err)
+
+		_, err = goparsify.Run(parsers["filter"], `name = bob`, goparsify.ASCIIWhitespace)
+		require.EqualError(t, err, "1:8: expected one of: '\"', 0-9\nname = bob\n       ^")
+	})
+
+	t.Run("actions", func(t *testing.T) {
+		parsers, err := Load(`
+			sum    <- number ("+" number)*
+			number <- [0-9]+
+		`, map[string]func(n *goparsify.Result){
+			"number": func(n *goparsify.Result) {
+				n.Result, _ = strconv.Atoi(n.Token)
+			},
+			"sum": func(n *goparsify.Result) {
+				total := n.Child[0].Result.(int)
+				for _, term := range n.Child[1].Child {
+					total += term.Child[1].Result.(int)
+				}
+				n.Result = total
+			},
+		})
+		require.NoError(t, err)
+
+		result, err := goparsify.Run(parsers["sum"], "1 + 20 + 300", goparsify.ASCIIWhitespace)
+		require.NoError(t, err)
+		require.Equal(t, 321, result)
+	})
+
+	t.Run("recursion", func(t *testing.T) {
+		parsers, err := Load(`parens <- "(" parens* ")"`, nil)
+		require.NoError(t, err)
+
+		_, err = goparsify.Run(parsers["parens"], "(()(()))")
+		require.NoError(t, err)
+
+		_, err = goparsify.Run(parsers["parens"], "(()")
+		require.Error(t, err)
+	})
+
+	t.Run("lookahead, dot and escapes", func(t *testing.T) {
+		parsers, err := Load(`
+			comment <- "/*" (!"*/" .)* "*/"
+			keyword <- "if" &[\t ]
+			quoted  <- '\'' [^'\\]* '\''
+		`, nil)
+		require.NoError(t, err)
+
+		_, err = goparsify.Run(parsers["comment"], "/* a * b */")
+		require.NoError(t, err)
+
+		_, err = goparsify.Run(parsers["keyword"], "if\t", goparsify.NoWhitespace)
+		require.EqualError(t, err, "1:3: left unparsed\nif\t\n  ^")
+
+		_, err = goparsify.Run(parsers["quoted"], `'it'`)
+		require.NoError(t, err)
+	})
+
+	t.Run("case insensitive literals", func(t *testing.T) {
+		parsers, err := Load(`select <- "select"i "x"`, nil)
+		require.NoError(t, err)
+
+		_, err = goparsify.Run(parsers["select"], "SeLeCt x")
+		require.NoError(t, err)
+	})
+
+	t.Run("definition errors", func(t *testing.T) {
+		_, err := Load(`a <- b "x"
+a <- c
+  c <- d`, map[string]func(n *goparsify.Result){"z": nil})
+		require.EqualError(t, err, `2:1: rule 'a' is defined more than once
+1:6: undefined rule 'b'
+3:8: undefined rule 'd'
+action for undefined rule 'z'`)
+
+		list := err.(goparsify.ErrorList)
+		require.Equal(t, &DefinitionError{Line: 2, Col: 1, Message: "rule 'a' is defined more than once"}, list[0])
+		require.Equal(t, &DefinitionError{Message: "action for undefined rule 'z'"}, list[3])
+	})
+
+	t.Run("repeating something that can match nothing", func(t *testing.T) {
+		_, err := Load(`x <- ("a"?)*`, nil)
+		require.Equal(t, goparsify.ErrorList{
+			&DefinitionError{Line: 1, Col: 6, Message: "repetition of something that can match nothing"},
+		}, err)
+
+		_, err = Load(`
+			list <- item+ "."
+			item <- [a-z]* / !"."
+		`, nil)
+		require.EqualError(t, err, "2:12: repetition of something that can match nothing")
+
+		parsers, err := Load(`x <- ("a" [b]*)* [c]*`, nil)
+		require.NoError(t, err)
+		_, err = goparsify.Run(parsers["x"], "aabbcc")
+		require.NoError(t, err)
+	})
+
+	t.Run("left recursion", func(t *testing.T) {
+		_, err := Load(`
+			expr <- expr "+" num / num
+			num  <- [0-9]+
+		`, nil)
+		require.Equal(t, goparsify.ErrorList{
+			&DefinitionError{Line: 2, Col: 12, Message: "rule 'expr' is left recursive: expr -> expr"},
+		}, err)
+
+		_, err = Load(`
+			a <- "x"? b
+			b <- &"y" c / "z"
+			c <- a "y"
+		`, nil)
+		require.EqualError(t, err, `2:14: rule 'a' is left recursive: a -> b -> c -> a
+3:14: rule 'b' is left recursive: b -> c -> a -> b
+4:9: rule 'c' is left recursive: c -> a -> b -> c`)
+
+		parsers, err := Load(`
+			expr <- num ("+" expr)?
+			num  <- [0-9]+
+		`, nil)
+		require.NoError(t, err)
+		_, err = goparsify.Run(parsers["expr"], "1 + 2 + 3")
+		require.NoError(t, err)
+	})
+
+	t.Run("syntax errors", func(t *testing.T) {
+		_, err := Load("a <- 'x'\nb <- (c", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "2:8: expected")
+		require.Contains(t, err.Error(), "')'")
+	})
+}
@@ -18,9 +18,12 @@ var (
 	tag Parser
 
 	identifier = Regex("[a-zA-Z][a-zA-Z0-9]*")
+	tagName    = Label(identifier, "tag name")
+	attrName   = Label(identifier, "attribute name")
 	text       = NotChars("<>").Map(func(n *Result) { n.Result = n.Token })
 
-	element  = Any(text, &tag)
+	// a broken tag is skipped up to the next close tag, so the rest of the document still gets checked
+	element  = Any(text, Recover(&tag, Seq("</", tagName, ">")))
 	elements = Some(element).Map(func(n *Result) {
 		ret := []interface{}{}
 		for _, child := range n.Child {
@@ -29,7 +32,7 @@ var (
 		n.Result = ret
 	})
 
-	attr  = Seq(identifier, "=", StringLit(`"'`))
+	attr  = Seq(attrName, "=", StringLit(`"'`))
 	attrs = Some(attr).Map(func(node *Result) {
 		attr := map[string]string{}
 
@@ -40,8 +43,8 @@ var (
 		node.Result = attr
 	})
 
-	tstart = Seq("<", identifier, Cut(), attrs, ">")
-	tend   = Seq("</", Cut(), identifier, ">")
+	tstart = Seq("<", tagName, Cut(), attrs, ">")
+	tend   = Seq("</", Cut(), tagName, ">")
 )
 
 func init() {
@@ -17,3 +17,23 @@ func TestParse(t *testing.T) {
 		htmlTag{Name: "p", Attributes: map[string]string{"color": "blue"}, Body: []interface{}{"world"}},
 	}}, result)
 }
+
+func TestParseErrors(t *testing.T) {
+	_, err := parse(`<body></1body>`)
+	require.Equal(t, "1:9: expected tag name\n<body></1body>\n        ^", err.Error())
+
+	_, err = parse(`<body 1="2"></body>`)
+	require.Equal(t, "1:7: expected one of: attribute name, '>'\n<body 1=\"2\"></body>\n      ^", err.Error())
+}
+
+func TestParseRecovers(t *testing.T) {
+	result, err := parse(`<body><div class=a>hi</div><p>ok</p><b x="1" y></b></body>`)
+	require.Equal(t, "1:18: expected \"'\n<body><div class=a>hi</div><p>ok</p><b x=\"1\" y></b></body>\n                 ^\n"+
+		"1:47: expected '='\n<body><div class=a>hi</div><p>ok</p><b x=\"1\" y></b></body>\n                                              ^", err.Error())
+
+	body := result.(htmlTag).Body
+	require.Len(t, body, 3)
+	require.IsType(t, &goparsify.Error{}, body[0])
+	require.Equal(t, htmlTag{Name: "p", Attributes: map[string]string{}, Body: []interface{}{"ok"}}, body[1])
+	require.IsType(t, &goparsify.Error{}, body[2])
+}
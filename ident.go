@@ -0,0 +1,178 @@
+package goparsify
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Keyword matches word only when it is not the start of a longer identifier, so Keyword("in") matches
+// "in x" but not "index". The word boundary follows the same unicode rules as UnicodeIdent.
+func Keyword(word string) Parser {
+	return keywordImpl(word, false)
+}
+
+// KeywordFold is Keyword ignoring case, eg KeywordFold("select") for SQL. The Token is the text as written.
+func KeywordFold(word string) Parser {
+	return keywordImpl(word, true)
+}
+
+func keywordImpl(word string, fold bool) Parser {
+	expected := "'" + word + "'"
+
+	return describe(NewParser(word, func(ps *State, node *Result) {
+		ps.WS(ps)
+		if ps.Errored() {
+			return
+		}
+		// folding can change the length of a rune, but never by more than it has
+		if len(ps.Input)-ps.Pos < len(word)*utf8.UTFMax {
+			ps.fill(ps.Pos + len(word)*utf8.UTFMax)
+		}
+
+		matched := len(word)
+		if fold {
+			matched = hasPrefixFold(ps.Get(), word)
+		} else if !strings.HasPrefix(ps.Get(), word) {
+			matched = -1
+		}
+		if matched == -1 || isIdentContinueAt(ps, ps.Pos+matched) {
+			ps.ErrorHere(expected)
+			return
+		}
+
+		node.Token = ps.token(ps.Pos, ps.Pos+matched)
+		node.Start = ps.Offset + ps.Pos
+		ps.Advance(matched)
+		node.End = ps.Offset + ps.Pos
+	}), func() *description {
+		return &description{kind: exactKind, text: word, fold: fold, word: true}
+	})
+}
+
+func isIdentContinueAt(ps *State, pos int) bool {
+	if !ps.has(pos) {
+		return false
+	}
+	if !utf8.FullRuneInString(ps.Input[pos:]) {
+		ps.fill(pos + utf8.UTFMax)
+	}
+	r, _ := utf8.DecodeRuneInString(ps.Input[pos:])
+	return isIdentContinue(r)
+}
+
+// Ident matches an identifier made of one of startChars followed by any number of restChars, using the same
+// format as Chars. Reserved words are rejected with an error saying so, eg:
+//  Ident("a-zA-Z_", "a-zA-Z0-9_", "if", "else", "for")
+func Ident(startChars, restChars string, reserved ...string) Parser {
+	return identImpl(runeMatcher(startChars), runeMatcher(restChars), reserved, false, identDescription(startChars, restChars))
+}
+
+// IdentFold is Ident with reserved words that are matched ignoring case, eg IdentFold("a-zA-Z_", "a-zA-Z0-9_", "select")
+// rejects SELECT and Select too.
+func IdentFold(startChars, restChars string, reserved ...string) Parser {
+	return identImpl(runeMatcher(startChars), runeMatcher(restChars), reserved, true, identDescription(startChars, restChars))
+}
+
+// UnicodeIdent matches an identifier following the unicode identifier rules, a letter or underscore followed
+// by letters, digits, marks and connector punctuation. This is close to XID_Start and XID_Continue, which the
+// unicode package doesn't have tables for.
+func UnicodeIdent(reserved ...string) Parser {
+	return identImpl(isIdentStart, isIdentContinue, reserved, false, unicodeIdentDescription)
+}
+
+// UnicodeIdentFold is UnicodeIdent with reserved words that are matched ignoring case
+func UnicodeIdentFold(reserved ...string) Parser {
+	return identImpl(isIdentStart, isIdentContinue, reserved, true, unicodeIdentDescription)
+}
+
+func identDescription(startChars, restChars string) func() *description {
+	return func() *description {
+		return &description{kind: namedKind, text: "identifier", expected: []string{"identifier"}, children: []*description{{
+			kind: seqKind,
+			children: []*description{
+				charsDescription(startChars, false, false, []int{1, 1})(),
+				charsDescription(restChars, false, false, []int{0})(),
+			},
+		}}}
+	}
+}
+
+// unicodeIdentFirst is every byte a unicode identifier can start with, any multi byte rune might be a letter
+var unicodeIdentFirst = func() string {
+	first := []byte("_abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	for b := utf8.RuneSelf; b <= 0xff; b++ {
+		first = append(first, byte(b))
+	}
+	return string(first)
+}()
+
+func unicodeIdentDescription() *description {
+	return &description{kind: namedKind, text: "identifier", first: unicodeIdentFirst, expected: []string{"identifier"}}
+}
+
+func identImpl(start, rest func(r rune) bool, reserved []string, fold bool, desc func() *description) Parser {
+	words := map[string]bool{}
+	for _, word := range reserved {
+		words[word] = true
+	}
+	isReserved := func(token string) bool {
+		if !fold {
+			return words[token]
+		}
+		for _, word := range reserved {
+			if strings.EqualFold(token, word) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return describe(NewParser("identifier", func(ps *State, node *Result) {
+		ps.WS(ps)
+		if ps.Errored() {
+			return
+		}
+		end := ps.Pos
+		for ps.has(end) {
+			if !utf8.FullRuneInString(ps.Input[end:]) {
+				ps.fill(end + utf8.UTFMax)
+			}
+			r, w := utf8.DecodeRuneInString(ps.Input[end:])
+			if end == ps.Pos && !start(r) || end > ps.Pos && !rest(r) {
+				break
+			}
+			end += w
+		}
+
+		if end == ps.Pos {
+			ps.ErrorHere("identifier")
+			return
+		}
+		if token := ps.Input[ps.Pos:end]; isReserved(token) {
+			ps.ErrorHere("identifier, not reserved word '" + token + "'")
+			return
+		}
+
+		node.Token = ps.token(ps.Pos, end)
+		node.Start = ps.Offset + ps.Pos
+		ps.Pos = end
+		node.End = ps.Offset + ps.Pos
+	}), desc)
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.Is(unicode.Nl, r)
+}
+
+func isIdentContinue(r rune) bool {
+	return isIdentStart(r) || unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc)
+}
+
+// runeMatcher turns a matcher in the format used by Chars into a func
+func runeMatcher(matcher string) func(r rune) bool {
+	alphabet, ranges := parseMatcher(matcher)
+	return func(r rune) bool {
+		return matchesRune(alphabet, ranges, r)
+	}
+}
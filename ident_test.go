@@ -0,0 +1,121 @@
+package goparsify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyword(t *testing.T) {
+	in := Keyword("in")
+
+	t.Run("success", func(t *testing.T) {
+		node, ps := runParser(" in x", in)
+		require.Equal(t, "in", node.Token)
+		require.Equal(t, 3, ps.Pos)
+		require.Equal(t, 1, node.Start)
+		require.Equal(t, 3, node.End)
+
+		_, ps = runParser("in(x)", in)
+		require.False(t, ps.Errored())
+
+		_, ps = runParser("in", in)
+		require.False(t, ps.Errored())
+	})
+
+	t.Run("needs a word boundary", func(t *testing.T) {
+		for _, input := range []string{"index", "in_x", "in2", "iné"} {
+			_, ps := runParser(input, in)
+			require.Equal(t, "'in'", ps.Error.expected, input)
+			require.Equal(t, 0, ps.Pos)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, ps := runParser("on", in)
+		require.Equal(t, "'in'", ps.Error.expected)
+	})
+
+	t.Run("case insensitive", func(t *testing.T) {
+		sel := KeywordFold("select")
+
+		node, ps := runParser("SeLeCt *", sel)
+		require.False(t, ps.Errored())
+		require.Equal(t, "SeLeCt", node.Token)
+
+		_, ps = runParser("selected", sel)
+		require.True(t, ps.Errored())
+
+		node, _ = runParser("ΣΙΣ", KeywordFold("σις"))
+		require.Equal(t, "ΣΙΣ", node.Token)
+
+		node, _ = runParser("Key", KeywordFold("key"))
+		require.Equal(t, "Key", node.Token)
+	})
+}
+
+func TestIdent(t *testing.T) {
+	ident := Ident("a-zA-Z_", "a-zA-Z0-9_", "if", "else")
+
+	t.Run("success", func(t *testing.T) {
+		node, ps := runParser("  foo_1 bar", ident)
+		require.Equal(t, "foo_1", node.Token)
+		require.Equal(t, 7, ps.Pos)
+		require.Equal(t, 2, node.Start)
+
+		node, _ = runParser("iffy", ident)
+		require.Equal(t, "iffy", node.Token)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, ps := runParser("1foo", ident)
+		require.Equal(t, "identifier", ps.Error.expected)
+		require.Equal(t, 0, ps.Pos)
+	})
+
+	t.Run("reserved words", func(t *testing.T) {
+		_, err := Run(Seq("let", ident), "let else")
+		require.EqualError(t, err, "1:5: expected identifier, not reserved word 'else'\nlet else\n    ^")
+	})
+
+	t.Run("unicode", func(t *testing.T) {
+		uident := UnicodeIdent("für")
+
+		node, ps := runParser("_größe2 = 1", uident)
+		require.False(t, ps.Errored())
+		require.Equal(t, "_größe2", node.Token)
+
+		node, _ = runParser("ŝ̂o‿x", uident)
+		require.Equal(t, "ŝ̂o‿x", node.Token)
+
+		_, ps = runParser("2x", uident)
+		require.True(t, ps.Errored())
+
+		_, ps = runParser("für", uident)
+		require.Equal(t, "identifier, not reserved word 'für'", ps.Error.expected)
+	})
+
+	t.Run("case insensitive reserved words", func(t *testing.T) {
+		fold := IdentFold("a-zA-Z_", "a-zA-Z0-9_", "select", "from")
+
+		_, ps := runParser("SELECT", fold)
+		require.Equal(t, "identifier, not reserved word 'SELECT'", ps.Error.expected)
+
+		node, ps := runParser("Selected", fold)
+		require.False(t, ps.Errored())
+		require.Equal(t, "Selected", node.Token)
+
+		_, ps = runParser("FÜR", UnicodeIdentFold("für"))
+		require.Equal(t, "identifier, not reserved word 'FÜR'", ps.Error.expected)
+
+		node, _ = runParser("SELECT", ident)
+		require.Equal(t, "SELECT", node.Token)
+	})
+
+	t.Run("description", func(t *testing.T) {
+		defer recordDescriptions()()
+		ident := Ident("a-zA-Z_", "a-zA-Z0-9_", "if", "else")
+		require.Equal(t, "identifier ::= [a-zA-Z_] [a-zA-Z0-9_]*\n", Describe(ident).EBNF())
+		require.Empty(t, Lint(Any(ident, UnicodeIdent())))
+	})
+}
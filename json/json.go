@@ -1,6 +1,8 @@
 package json
 
 import (
+	"sync"
+
 	. "github.com/vektah/goparsify"
 )
 
@@ -12,31 +14,62 @@ var (
 	_string     = Map(StringLit(`"`), func(r *Result) { r.Result = r.Token })
 	_number     = NumberLit()
 	_properties = Some(Seq(StringLit(`"`), ":", &_value), ",")
+	_array      = Seq("[", Cut(), Some(&_value, ","), "]").Map(toSlice)
+	_object     = Seq("{", Cut(), _properties, "}").Map(toMap)
 
-	_array = Seq("[", Cut(), Some(&_value, ","), "]").Map(func(n *Result) {
-		ret := []interface{}{}
-		for _, child := range n.Child[2].Child {
-			ret = append(ret, child.Result)
-		}
-		n.Result = ret
-	})
-
-	_object = Seq("{", Cut(), _properties, "}").Map(func(n *Result) {
-		ret := map[string]interface{}{}
-
-		for _, prop := range n.Child[2].Child {
-			ret[prop.Child[0].Token] = prop.Child[2].Result
-		}
-
-		n.Result = ret
-	})
+	// the same grammar for UnmarshalAll, except when an array or object is broken it skips to the end of it and
+	// carries on, so every problem gets reported
+	_recovering       Parser
+	_element          = Recover(&_recovering, Any("]", "}"))
+	_recoveringArray  = Seq("[", Cut(), Some(_element, ","), "]").Map(toSlice)
+	_recoveringObject = Seq("{", Cut(), Some(Seq(StringLit(`"`), ":", _element), ","), "}").Map(toMap)
 )
 
+// the results of a parse are all turned into plain values by Map, so its Arena can be reused straight away
+var arenas = sync.Pool{New: func() interface{} { return NewArena() }}
+
 func init() {
 	_value = Any(_null, _true, _false, _string, _number, _array, _object)
+	_recovering = Any(_null, _true, _false, _string, _number, _recoveringArray, _recoveringObject)
+}
+
+func toSlice(n *Result) {
+	ret := []interface{}{}
+	for _, child := range n.Child[2].Child {
+		ret = append(ret, child.Result)
+	}
+	n.Result = ret
+}
+
+func toMap(n *Result) {
+	ret := map[string]interface{}{}
+
+	for _, prop := range n.Child[2].Child {
+		ret[prop.Child[0].Token] = prop.Child[2].Result
+	}
+
+	n.Result = ret
 }
 
 // Unmarshall json string into map[string]interface{} or []interface{}
 func Unmarshal(input string) (interface{}, error) {
-	return Run(_value, input, ASCIIWhitespace)
+	return unmarshal(_value, input)
+}
+
+// UnmarshalAll is Unmarshal for input that may be broken in several places. Broken values nested inside arrays
+// or objects are replaced by their *goparsify.Error, and all of them are returned in a goparsify.ErrorList.
+func UnmarshalAll(input string) (interface{}, error) {
+	return unmarshal(_recovering, input)
+}
+
+func unmarshal(value Parser, input string) (interface{}, error) {
+	arena := arenas.Get().(*Arena)
+	defer func() {
+		arena.Release()
+		arenas.Put(arena)
+	}()
+
+	ps := NewState(input)
+	ps.Arena = arena
+	return RunState(value, ps, ASCIIWhitespace)
 }
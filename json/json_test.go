@@ -41,6 +41,34 @@ func TestUnmarshal(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, map[string]interface{}{"true": true, "false": false, "null": nil, "number": int64(404)}, result)
 	})
+
+	t.Run("stops at the first broken value", func(t *testing.T) {
+		result, err := Unmarshal(`{"a": [1 2], "b": true, "c": {"d" 3}, "e": [4]}`)
+		require.EqualError(t, err, "1:10: expected one of: ',', ']'\n"+`{"a": [1 2], "b": true, "c": {"d" 3}, "e": [4]}`+"\n         ^")
+		require.IsType(t, &goparsify.Error{}, err)
+		require.Nil(t, result)
+	})
+}
+
+func TestUnmarshalAll(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		result, err := UnmarshalAll(`{"a": [1, 2], "b": {"c": null}}`)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"a": []interface{}{int64(1), int64(2)}, "b": map[string]interface{}{"c": nil}}, result)
+	})
+
+	t.Run("reports every broken value", func(t *testing.T) {
+		result, err := UnmarshalAll(`{"a": [1 2], "b": true, "c": {"d" 3}, "e": [4]}`)
+		require.EqualError(t, err, "1:10: expected one of: ',', ']'\n"+`{"a": [1 2], "b": true, "c": {"d" 3}, "e": [4]}`+"\n         ^\n"+
+			"1:35: expected ':'\n"+`{"a": [1 2], "b": true, "c": {"d" 3}, "e": [4]}`+"\n                                  ^")
+		require.Len(t, err.(goparsify.ErrorList), 2)
+
+		object := result.(map[string]interface{})
+		require.IsType(t, &goparsify.Error{}, object["a"])
+		require.IsType(t, &goparsify.Error{}, object["c"])
+		require.Equal(t, true, object["b"])
+		require.Equal(t, []interface{}{int64(4)}, object["e"])
+	})
 }
 
 func BenchmarkUnmarshalParsec(b *testing.B) {
@@ -63,6 +91,30 @@ func BenchmarkUnmarshalParsify(b *testing.B) {
 	goparsify.DumpDebugStats()
 }
 
+// Unmarshal without an Arena, so every Result's children are allocated on their own
+func BenchmarkUnmarshalParsifyNoArena(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, err := goparsify.Run(_value, benchmarkString, goparsify.ASCIIWhitespace)
+		require.NoError(b, err)
+	}
+}
+
+// The same grammar as Unmarshal without building results, but every value is memoized. JSON barely backtracks so
+// this mostly shows the cost of the cache, see BenchmarkBacktrackingMemo for the other side of the trade-off.
+func BenchmarkUnmarshalParsifyMemo(b *testing.B) {
+	var value goparsify.Parser
+	memoValue := goparsify.Memo(&value)
+	properties := goparsify.Some(goparsify.Seq(goparsify.StringLit(`"`), ":", memoValue), ",")
+	array := goparsify.Seq("[", goparsify.Cut(), goparsify.Some(memoValue, ","), "]")
+	object := goparsify.Seq("{", goparsify.Cut(), properties, "}")
+	value = goparsify.Any(_null, _true, _false, _string, _number, array, object)
+
+	for i := 0; i < b.N; i++ {
+		_, err := goparsify.Run(memoValue, benchmarkString, goparsify.ASCIIWhitespace)
+		require.NoError(b, err)
+	}
+}
+
 func BenchmarkUnmarshalStdlib(b *testing.B) {
 	bytes := []byte(benchmarkString)
 	var result interface{}
@@ -0,0 +1,79 @@
+package json
+
+import (
+	"sort"
+
+	"github.com/vektah/goparsify/pretty"
+)
+
+var (
+	_syntax   pretty.Syntax
+	_item     = pretty.Ref(&_syntax)
+	_property = pretty.Seq(pretty.StringLit(), pretty.Exact(":"), pretty.Space, _item)
+
+	_arraySyntax = pretty.Map(
+		pretty.Seq(pretty.Exact("["), list(_item), pretty.SoftBreak, pretty.Exact("]")).Group(),
+		func(v interface{}) interface{} {
+			return v.([]interface{})[1].([]interface{})[1]
+		},
+		func(v interface{}) (interface{}, bool) {
+			items, ok := v.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			return []interface{}{nil, []interface{}{nil, items}, nil, nil}, true
+		},
+	)
+
+	_objectSyntax = pretty.Map(
+		pretty.Seq(pretty.Exact("{"), list(_property), pretty.SoftBreak, pretty.Exact("}")).Group(),
+		func(v interface{}) interface{} {
+			ret := map[string]interface{}{}
+			for _, prop := range v.([]interface{})[1].([]interface{})[1].([]interface{}) {
+				prop := prop.([]interface{})
+				ret[prop[0].(string)] = prop[3]
+			}
+			return ret
+		},
+		func(v interface{}) (interface{}, bool) {
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			keys := make([]string, 0, len(obj))
+			for key := range obj {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			props := make([]interface{}, len(keys))
+			for i, key := range keys {
+				props[i] = []interface{}{key, nil, nil, obj[key]}
+			}
+			return []interface{}{nil, []interface{}{nil, props}, nil, nil}, true
+		},
+	)
+)
+
+func init() {
+	_syntax = pretty.Any(
+		pretty.Bind(pretty.Exact("null"), nil),
+		pretty.Bind(pretty.Exact("true"), true),
+		pretty.Bind(pretty.Exact("false"), false),
+		pretty.StringLit(),
+		pretty.NumberLit(),
+		_arraySyntax,
+		_objectSyntax,
+	)
+}
+
+// list is the comma separated part of an array or object, broken over indented lines when it doesn't fit
+func list(item pretty.Syntax) pretty.Syntax {
+	return pretty.Seq(pretty.SoftBreak, pretty.Some(item, pretty.Seq(pretty.Exact(","), pretty.LineBreak))).Nest()
+}
+
+// Marshal is the reverse of Unmarshal, it prints json that fits in 80 columns where it can, indented by two spaces.
+// Object keys are sorted.
+func Marshal(v interface{}) (string, error) {
+	return _syntax.Format(v, 80, "  ")
+}
@@ -0,0 +1,63 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/goparsify"
+)
+
+func TestMarshal(t *testing.T) {
+	t.Run("basic types", func(t *testing.T) {
+		for _, v := range []interface{}{nil, true, false, "a\"b", int64(-4), 1.5} {
+			text, err := Marshal(v)
+			require.NoError(t, err)
+			result, err := Unmarshal(text)
+			require.NoError(t, err)
+			require.Equal(t, v, result)
+		}
+	})
+
+	t.Run("short values stay on one line", func(t *testing.T) {
+		text, err := Marshal(map[string]interface{}{"b": []interface{}{int64(1), 2.0}, "a": map[string]interface{}{}})
+		require.NoError(t, err)
+		require.Equal(t, `{"a": {}, "b": [1, 2.0]}`, text)
+	})
+
+	t.Run("long values are indented", func(t *testing.T) {
+		text, err := Marshal(map[string]interface{}{
+			"servlet-name":  "cofaxCDS",
+			"servlet-class": "org.cofax.cds.CDSServlet",
+			"init-param":    map[string]interface{}{"log": int64(1), "betaServer": true},
+		})
+		require.NoError(t, err)
+		require.Equal(t, `{
+  "init-param": {"betaServer": true, "log": 1},
+  "servlet-class": "org.cofax.cds.CDSServlet",
+  "servlet-name": "cofaxCDS"
+}`, text)
+	})
+
+	t.Run("round trips", func(t *testing.T) {
+		expected, err := Unmarshal(benchmarkString)
+		require.NoError(t, err)
+
+		text, err := Marshal(expected)
+		require.NoError(t, err)
+		result, err := Unmarshal(text)
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+
+		result, err = _syntax.Parse(text, goparsify.ASCIIWhitespace)
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("unsupported values", func(t *testing.T) {
+		_, err := Marshal(map[string]interface{}{"a": struct{}{}})
+		require.EqualError(t, err, "cant print a `struct {}`")
+
+		_, err = Marshal([]interface{}{int64(1), []interface{}{int8(2)}})
+		require.EqualError(t, err, "cant print a `int8`")
+	})
+}
@@ -0,0 +1,108 @@
+package goparsify
+
+import "sync/atomic"
+
+type seed struct {
+	result Result
+	end    int
+	cut    int
+	// recovered is what was recovered from during the match
+	recovered []Error
+	matched   bool
+	recursed  bool
+}
+
+// LeftRec allows a parser to refer back to itself from its leftmost position, directly or
+// indirectly through other *Parser references, which would otherwise recurse forever:
+//  var expr Parser
+//  expr = LeftRec(Any(Seq(&expr, "-", NumberLit()), NumberLit()))
+//
+// It grows the match iteratively (Warth et al, "Packrat Parsers Can Support Left Recursion"). The first
+// recursive call at a position fails, giving the non recursive alternatives a chance to match a seed.
+// The parser is then run again, with recursive calls returning the previous match, for as long as the
+// match keeps getting longer. Only one parser in each recursive cycle needs to be wrapped, but none of
+// the parsers in the cycle should be wrapped in Memo.
+//
+// Nothing finds left recursion for you, every cycle has to be wrapped by hand, Lint reports the ones that
+// aren't. Cycles that share rules, eg a rule that is left recursive both on itself and through another
+// left recursive rule, aren't handled: the "involved set" bookkeeping from the paper isn't done, so only the
+// LeftRec entered first grows and the other cycle may stop short. There also has to be a way to match
+// without recursing, LeftRec(Seq(&expr, "-", "1")) can never match anything and Lint reports that too.
+func LeftRec(parser Parserish) Parser {
+	p := Parsify(parser)
+	id := atomic.AddInt64(&memoIDs, 1)
+
+	return describe(NewParser("LeftRec()", func(ps *State, node *Result) {
+		startpos := ps.Offset + ps.Pos
+		key := memoKey{id: id, pos: startpos, ws: wsID(ps.WS)}
+
+		tables := ps.tables()
+		if s, ok := tables.growing[key]; ok {
+			if s.matched {
+				*node = s.result
+				ps.rewind(s.end)
+				ps.Recovered = append(ps.Recovered, s.recovered...)
+				return
+			}
+			s.recursed = true
+			ps.Error.pos = startpos
+			ps.Error.expected = "left recursion"
+			ps.Error.input = ps.Input
+			ps.Error.discarded = ps.discardedInput()
+			return
+		}
+
+		if tables.growing == nil {
+			tables.growing = map[memoKey]*seed{}
+		}
+		s := &seed{}
+		tables.growing[key] = s
+
+		// every pass starts again from startpos, so a stream has to keep it even once a pass has cut past it
+		hold := ps.holdFrom(startpos)
+		defer func() {
+			delete(tables.growing, key)
+			ps.release(hold)
+		}()
+
+		cut, recovered := ps.Cut, len(ps.Recovered)
+
+		p(ps, node)
+		if ps.Errored() || !s.recursed {
+			return
+		}
+
+		for {
+			s.result, s.end, s.cut, s.matched = *node, ps.Offset+ps.Pos, ps.Cut, true
+			s.recovered = append(s.recovered[:0], ps.Recovered[recovered:]...)
+			ps.Arena.keep()
+
+			// each pass matches the same input again, so it finds the same errors again
+			ps.rewind(startpos)
+			ps.forget(recovered)
+			ps.Cut = cut
+			next := Result{}
+			p(ps, &next)
+			if ps.Errored() {
+				if ps.Cut != cut && ps.Cut > startpos {
+					ps.rewind(startpos)
+					ps.forget(recovered)
+					return
+				}
+				ps.Recover()
+				break
+			}
+			if ps.Offset+ps.Pos <= s.end {
+				break
+			}
+			*node = next
+		}
+
+		*node = s.result
+		ps.rewind(s.end)
+		ps.Cut = s.cut
+		ps.Recovered = append(ps.Recovered[:recovered], s.recovered...)
+	}), func() *description {
+		return &description{kind: leftRecKind, children: lookupDescriptions([]Parser{p})}
+	})
+}
@@ -0,0 +1,85 @@
+package goparsify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeftRec(t *testing.T) {
+	number := NumberLit()
+
+	var expr Parser
+	expr = LeftRec(Any(
+		Seq(&expr, "-", number).Map(func(n *Result) {
+			n.Result = n.Child[0].Result.(int64) - n.Child[2].Result.(int64)
+		}),
+		number,
+	))
+
+	t.Run("seed", func(t *testing.T) {
+		result, err := Run(expr, "10")
+		require.NoError(t, err)
+		require.Equal(t, int64(10), result)
+	})
+
+	t.Run("grows left associative", func(t *testing.T) {
+		result, err := Run(expr, "10 - 3 - 2")
+		require.NoError(t, err)
+		require.Equal(t, int64(5), result)
+	})
+
+	t.Run("stops at the longest match", func(t *testing.T) {
+		node, ps := runParser("10 - 3 - x", expr)
+		require.False(t, ps.Errored())
+		require.Equal(t, int64(7), node.Result)
+		require.Equal(t, 6, ps.Pos)
+		require.Equal(t, 0, node.Start)
+		require.Equal(t, 6, node.End)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, ps := runParser("x", expr)
+		require.True(t, ps.Errored())
+		require.Equal(t, 0, ps.Error.Pos())
+		require.Equal(t, 0, ps.Pos)
+	})
+
+	t.Run("indirect", func(t *testing.T) {
+		concat := func(n *Result) {
+			n.Result = n.Child[0].Result.(string) + n.Child[1].Token
+		}
+		token := func(n *Result) {
+			n.Result = n.Token
+		}
+
+		var a, b Parser
+		a = LeftRec(Any(Seq(&b, "x").Map(concat), Map("a", token)))
+		b = Any(Seq(&a, "y").Map(concat), Map("b", token))
+
+		result, err := Run(a, "ayxyx")
+		require.NoError(t, err)
+		require.Equal(t, "ayxyx", result)
+
+		result, err = Run(a, "bx")
+		require.NoError(t, err)
+		require.Equal(t, "bx", result)
+	})
+
+	t.Run("cut", func(t *testing.T) {
+		var sum Parser
+		sum = LeftRec(Any(Seq(&sum, "+", Cut(), number), number))
+
+		_, ps := runParser("1 + 2 +", sum)
+		require.Equal(t, "number", ps.Error.expected)
+		require.Equal(t, 7, ps.Error.Pos())
+		require.Equal(t, 0, ps.Pos)
+
+		node, ps := runParser("1 + 2 + 3", sum)
+		require.False(t, ps.Errored())
+		require.Equal(t, 7, ps.Cut)
+		require.Equal(t, 9, ps.Pos)
+		require.Equal(t, "+", node.Child[1].Token)
+		require.Equal(t, "+", node.Child[0].Child[1].Token)
+	})
+}
@@ -0,0 +1,139 @@
+package goparsify
+
+import (
+	"context"
+	"fmt"
+)
+
+// checkEvery is how many steps go by between checks of State.Context
+const checkEvery = 1024
+
+// StepLimitError is returned when a parse runs more parsers than State.MaxSteps allows
+type StepLimitError struct {
+	Limit int
+	// Pos is the offset into the document the parse had got to when it stopped
+	Pos int
+}
+
+func (e *StepLimitError) Error() string {
+	return fmt.Sprintf("offset %d: parse took more than %d steps", e.Pos, e.Limit)
+}
+
+// DepthLimitError is returned when a parse goes through more *Parser references at once than State.MaxDepth allows
+type DepthLimitError struct {
+	Limit int
+	Pos   int
+}
+
+func (e *DepthLimitError) Error() string {
+	return fmt.Sprintf("offset %d: parse nested more than %d deep", e.Pos, e.Limit)
+}
+
+// NodeLimitError is returned when a parse builds more Results than State.MaxNodes allows
+type NodeLimitError struct {
+	Limit int
+	Pos   int
+}
+
+func (e *NodeLimitError) Error() string {
+	return fmt.Sprintf("offset %d: parse built more than %d results", e.Pos, e.Limit)
+}
+
+// RunContext is Run that stops early with ctx.Err() when ctx is cancelled or its deadline passes. Combine it with
+// the limits on State, through RunState, to parse untrusted input.
+func RunContext(ctx context.Context, parser Parserish, input string, ws ...VoidParser) (result interface{}, err error) {
+	ps := NewState(input)
+	ps.Context = ctx
+	return run(Parsify(parser), ps, ws)
+}
+
+// counters is what has been counted against the limits, it is only allocated once a limit or Context is set
+type counters struct {
+	steps, depth, nodes int
+	nextCheck           int
+	// abort is why the parse was stopped by a limit
+	abort error
+}
+
+// step is called by combinators each time they run, with how many Results they are about to build. It returns
+// false when the parse has to stop, leaving an error that Recover can't clear.
+func (s *State) step(nodes int) bool {
+	c := s.counters
+	if c == nil {
+		if s.MaxSteps == 0 && s.MaxDepth == 0 && s.MaxNodes == 0 && s.Context == nil {
+			return true
+		}
+		c = &counters{}
+		s.counters = c
+	}
+	c.steps++
+	c.nodes += nodes
+	if c.steps < c.nextCheck && (s.MaxNodes == 0 || c.nodes <= s.MaxNodes) {
+		return true
+	}
+	return s.checkLimits()
+}
+
+func (s *State) checkLimits() bool {
+	c := s.counters
+	if c.abort == nil {
+		pos := s.Offset + s.Pos
+		switch {
+		case s.MaxSteps > 0 && c.steps > s.MaxSteps:
+			c.abort = &StepLimitError{Limit: s.MaxSteps, Pos: pos}
+		case s.MaxNodes > 0 && c.nodes > s.MaxNodes:
+			c.abort = &NodeLimitError{Limit: s.MaxNodes, Pos: pos}
+		case s.MaxDepth > 0 && c.depth > s.MaxDepth:
+			c.abort = &DepthLimitError{Limit: s.MaxDepth, Pos: pos}
+		case s.Context != nil:
+			select {
+			case <-s.Context.Done():
+				c.abort = s.Context.Err()
+			default:
+			}
+		}
+	}
+
+	if c.abort != nil {
+		// every step from now on fails too, in case a parser put back an older error
+		c.nextCheck = 0
+		s.ErrorHere(c.abort.Error())
+		return false
+	}
+
+	c.nextCheck = c.steps + checkEvery
+	if s.MaxSteps > 0 && s.MaxSteps+1 < c.nextCheck {
+		c.nextCheck = s.MaxSteps + 1
+	}
+	return true
+}
+
+// enter is step for a *Parser, which also counts how deep the parse is. Every enter that returns true needs a
+// leave once the parser is done.
+func (s *State) enter() bool {
+	if !s.step(0) {
+		return false
+	}
+	if c := s.counters; c != nil {
+		c.depth++
+		if s.MaxDepth > 0 && c.depth > s.MaxDepth && !s.checkLimits() {
+			c.depth--
+			return false
+		}
+	}
+	return true
+}
+
+func (s *State) leave() {
+	if s.counters != nil {
+		s.counters.depth--
+	}
+}
+
+// aborted is the error a limit stopped the parse with, if one has
+func (s *State) aborted() error {
+	if s.counters == nil {
+		return nil
+	}
+	return s.counters.abort
+}
@@ -0,0 +1,81 @@
+package goparsify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimits(t *testing.T) {
+	var list Parser
+	list = Seq("[", Some(&list, ","), "]")
+	nested := strings.Repeat("[", 50) + strings.Repeat("]", 50)
+
+	run := func(setup func(ps *State)) error {
+		ps := NewState(nested)
+		setup(ps)
+		_, err := RunState(list, ps)
+		return err
+	}
+
+	t.Run("no limits", func(t *testing.T) {
+		require.NoError(t, run(func(ps *State) {}))
+	})
+
+	t.Run("steps", func(t *testing.T) {
+		err := run(func(ps *State) { ps.MaxSteps = 100 })
+		require.Equal(t, &StepLimitError{Limit: 100, Pos: 34}, err)
+		require.EqualError(t, err, "offset 34: parse took more than 100 steps")
+
+		require.NoError(t, run(func(ps *State) { ps.MaxSteps = 1000 }))
+	})
+
+	t.Run("depth", func(t *testing.T) {
+		err := run(func(ps *State) { ps.MaxDepth = 10 })
+		require.Equal(t, &DepthLimitError{Limit: 10, Pos: 11}, err)
+
+		require.NoError(t, run(func(ps *State) { ps.MaxDepth = 50 }))
+	})
+
+	t.Run("nodes", func(t *testing.T) {
+		err := run(func(ps *State) { ps.MaxNodes = 30 })
+		require.IsType(t, &NodeLimitError{}, err)
+		require.Equal(t, 30, err.(*NodeLimitError).Limit)
+	})
+
+	t.Run("can't be recovered or backtracked out of", func(t *testing.T) {
+		p := Some(Any(Recover(Seq("[", Cut(), Some(&list, ","), "]"), "]"), "x"))
+		ps := NewState(nested)
+		ps.MaxDepth = 10
+		_, err := RunState(p, ps)
+		require.IsType(t, &DepthLimitError{}, err)
+	})
+}
+
+func TestRunContext(t *testing.T) {
+	t.Run("finishes", func(t *testing.T) {
+		result, err := RunContext(context.Background(), Bind("a", 1), "a")
+		require.NoError(t, err)
+		require.Equal(t, 1, result)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := RunContext(ctx, Some(Seq("a", "b")), strings.Repeat("ab", 10))
+		require.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := RunContext(ctx, backtrackingExpr(Parsify), strings.Repeat("(", 30)+"1"+strings.Repeat(")", 30))
+		require.Equal(t, context.DeadlineExceeded, err)
+		require.True(t, time.Since(start) < time.Second)
+	})
+}
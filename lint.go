@@ -0,0 +1,332 @@
+package goparsify
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LintError is a problem found by Lint, Location is where the parser was built when it is known
+type LintError struct {
+	Location string
+	Message  string
+}
+
+func (e *LintError) Error() string {
+	if e.Location == "" {
+		return e.Message
+	}
+	return e.Location + ": " + e.Message
+}
+
+type linter struct {
+	errors   ErrorList
+	seen     map[*description]bool
+	nullable map[*description]bool
+	always   map[*description]bool
+	targets  []*description
+}
+
+// Lint looks for common mistakes in a grammar, using the descriptions kept by RecordDescriptions:
+//  - Any alternatives that can never match, because an earlier one matches a prefix of them, eg Any("in", "int"),
+//    or because an earlier one always matches
+//  - Some or Many of something that can match without consuming input, which would repeat forever
+//  - *Parser references that are still nil
+//  - rules that refer to themselves before consuming input, which recurse until the stack runs out unless
+//    wrapped in LeftRec
+//  - LeftRec rules that can't match without recursing, so they never match anything
+//  - any of the given rules that can't be reached from parser
+// Every problem found is returned in the ErrorList, as a *LintError, along with where it is.
+func Lint(parser Parserish, rules ...Parserish) ErrorList {
+	l := &linter{
+		seen:     map[*description]bool{},
+		nullable: map[*description]bool{},
+		always:   map[*description]bool{},
+	}
+
+	root := lookupDescription(Parsify(parser))
+	if resolve(root).kind == unknownKind {
+		return ErrorList{&LintError{Message: "parser has no description, call RecordDescriptions before building it"}}
+	}
+	l.walk(root)
+
+	for _, target := range l.targets {
+		if target.kind == leftRecKind {
+			if !l.grounded(target.children[0], target, map[*description]bool{}) {
+				l.report(target, "%s has no way to match without recursing, it needs a non recursive alternative", ruleName(target))
+			}
+			continue
+		}
+		if l.leftmost(target, target, map[*description]bool{}) {
+			l.report(target, "%s is left recursive, wrap it in LeftRec", ruleName(target))
+		}
+	}
+
+	for _, rule := range rules {
+		d := resolve(lookupDescription(Parsify(rule)))
+		if !l.seen[d] {
+			l.report(d, "%s is never used", ruleName(d))
+		}
+	}
+
+	return l.errors
+}
+
+func (l *linter) report(d *description, format string, args ...interface{}) {
+	l.errors = append(l.errors, &LintError{Location: d.location, Message: fmt.Sprintf(format, args...)})
+}
+
+func ruleName(d *description) string {
+	if d.kind == namedKind {
+		return d.text
+	}
+	return "rule"
+}
+
+// walk visits everything reachable from d once, checking each part as it goes
+func (l *linter) walk(d *description) {
+	if d.kind == refKind {
+		if *d.ref == nil {
+			l.report(d, "reference to a *Parser that is still nil")
+			return
+		}
+		target := resolve(d)
+		if !l.seen[target] {
+			l.targets = append(l.targets, target)
+		}
+		d = target
+	}
+	if l.seen[d] {
+		return
+	}
+	l.seen[d] = true
+
+	switch d.kind {
+	case anyKind:
+		l.checkAny(d)
+	case someKind, manyKind:
+		name := "Some"
+		if d.kind == manyKind {
+			name = "Many"
+		}
+		if l.isNullable(d.children[0]) && (len(d.children) == 1 || l.isNullable(d.children[1])) {
+			l.report(d, "%s repeats something that can match without consuming input, it would never stop", name)
+		}
+	}
+
+	for _, child := range d.children {
+		l.walk(child)
+	}
+}
+
+func (l *linter) checkAny(d *description) {
+	for i, earlier := range d.children {
+		if l.alwaysMatches(earlier) && i < len(d.children)-1 {
+			l.report(d, "Any alternative %d always matches, so the ones after it are never tried", i+1)
+			return
+		}
+
+		prefix := resolve(earlier)
+		if prefix.kind != exactKind || prefix.word {
+			continue
+		}
+		for _, later := range d.children[i+1:] {
+			literal, fold := firstLiteral(later, 0)
+			if literal == "" || fold && !prefix.fold {
+				continue
+			}
+			if prefix.fold && hasPrefixFold(literal, prefix.text) == -1 || !prefix.fold && !strings.HasPrefix(literal, prefix.text) {
+				continue
+			}
+			l.report(d, "Any alternative %s can never match, %s matches first",
+				strconv.Quote(literal), strconv.Quote(prefix.text))
+		}
+	}
+}
+
+// firstLiteral is the literal that d has to start with, if it has one
+func firstLiteral(d *description, depth int) (literal string, fold bool) {
+	d = resolve(d)
+	if depth > 100 {
+		return "", false
+	}
+	switch d.kind {
+	case exactKind:
+		if d.text != "" {
+			return d.text, d.fold
+		}
+	case seqKind:
+		for _, child := range d.children {
+			if child.kind != emptyKind {
+				return firstLiteral(child, depth+1)
+			}
+		}
+	case namedKind, leftRecKind, manyKind:
+		if len(d.children) > 0 {
+			return firstLiteral(d.children[0], depth+1)
+		}
+	}
+	return "", false
+}
+
+// isNullable is whether d can match without consuming anything
+func (l *linter) isNullable(d *description) bool {
+	d = resolve(d)
+	if result, ok := l.nullable[d]; ok {
+		return result
+	}
+	// a rule that is still being worked out has to consume something to get back here
+	l.nullable[d] = false
+
+	result := false
+	switch d.kind {
+	case exactKind:
+		result = d.text == ""
+	case charsKind:
+		result = d.min == 0
+	case emptyKind, someKind, maybeKind, notKind, peekKind:
+		result = true
+	case seqKind:
+		result = true
+		for _, child := range d.children {
+			if !l.isNullable(child) {
+				result = false
+				break
+			}
+		}
+	case anyKind:
+		for _, child := range d.children {
+			if l.isNullable(child) {
+				result = true
+				break
+			}
+		}
+	case manyKind, namedKind, leftRecKind:
+		result = len(d.children) > 0 && l.isNullable(d.children[0])
+	}
+
+	l.nullable[d] = result
+	return result
+}
+
+// alwaysMatches is whether d can never fail
+func (l *linter) alwaysMatches(d *description) bool {
+	d = resolve(d)
+	if result, ok := l.always[d]; ok {
+		return result
+	}
+	l.always[d] = false
+
+	result := false
+	switch d.kind {
+	case exactKind:
+		result = d.text == ""
+	case charsKind:
+		result = d.min == 0
+	case emptyKind, someKind, maybeKind:
+		result = true
+	case seqKind:
+		result = true
+		for _, child := range d.children {
+			if !l.alwaysMatches(child) {
+				result = false
+				break
+			}
+		}
+	case anyKind:
+		for _, child := range d.children {
+			if l.alwaysMatches(child) {
+				result = true
+				break
+			}
+		}
+	case manyKind, namedKind, leftRecKind:
+		result = len(d.children) > 0 && l.alwaysMatches(d.children[0])
+	}
+
+	l.always[d] = result
+	return result
+}
+
+// leftmost is whether target can be reached from d without consuming any input
+func (l *linter) leftmost(d *description, target *description, seen map[*description]bool) bool {
+	if d.kind == refKind {
+		d = resolve(d)
+		if d == target {
+			return true
+		}
+	}
+	if seen[d] {
+		return false
+	}
+	seen[d] = true
+
+	switch d.kind {
+	case seqKind:
+		for _, child := range d.children {
+			if l.leftmost(child, target, seen) {
+				return true
+			}
+			if !l.isNullable(child) {
+				return false
+			}
+		}
+	case anyKind:
+		return l.leftmostAny(d.children, target, seen)
+	case someKind, manyKind, maybeKind, notKind, peekKind, namedKind:
+		if len(d.children) > 0 {
+			return l.leftmost(d.children[0], target, seen)
+		}
+	}
+	// LeftRec deals with its own left recursion
+	return false
+}
+
+// grounded is whether d can match without reaching target before consuming any input
+func (l *linter) grounded(d *description, target *description, seen map[*description]bool) bool {
+	if d.kind == refKind {
+		d = resolve(d)
+		if d == target {
+			return false
+		}
+	}
+	if seen[d] {
+		// coming back around before consuming anything doesn't lead anywhere new
+		return false
+	}
+	seen[d] = true
+	defer delete(seen, d)
+
+	switch d.kind {
+	case seqKind:
+		for _, child := range d.children {
+			if !l.grounded(child, target, seen) {
+				return false
+			}
+			if !l.isNullable(child) {
+				return true
+			}
+		}
+	case anyKind:
+		for _, child := range d.children {
+			if l.grounded(child, target, seen) {
+				return true
+			}
+		}
+		return false
+	case manyKind, namedKind, leftRecKind:
+		if len(d.children) > 0 {
+			return l.grounded(d.children[0], target, seen)
+		}
+	}
+	return true
+}
+
+func (l *linter) leftmostAny(children []*description, target *description, seen map[*description]bool) bool {
+	for _, child := range children {
+		if l.leftmost(child, target, seen) {
+			return true
+		}
+	}
+	return false
+}
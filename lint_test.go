@@ -0,0 +1,94 @@
+package goparsify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func lintMessages(errs ErrorList) []string {
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.(*LintError).Message)
+	}
+	return messages
+}
+
+func TestLint(t *testing.T) {
+	defer recordDescriptions()()
+
+	t.Run("clean grammar", func(t *testing.T) {
+		var value Parser
+		array := Seq("[", Cut(), Some(&value, ","), "]")
+		value = Any(Bind("null", nil), NumberLit(), array)
+		require.Empty(t, Lint(&value))
+	})
+
+	t.Run("prefix shadows a later alternative", func(t *testing.T) {
+		errs := Lint(Any("in", Seq("int", Chars("0-9")), Keyword("if"), "ifdef", ExactFold("x"), "XY"))
+		require.Equal(t, []string{
+			`Any alternative "int" can never match, "in" matches first`,
+			`Any alternative "XY" can never match, "x" matches first`,
+		}, lintMessages(errs))
+		require.Regexp(t, `^lint_test.go:\d+$`, errs[0].(*LintError).Location)
+	})
+
+	t.Run("alternative that always matches", func(t *testing.T) {
+		require.Equal(t, []string{
+			"Any alternative 1 always matches, so the ones after it are never tried",
+		}, lintMessages(Lint(Any(Maybe("a"), "b"))))
+	})
+
+	t.Run("repeating something that matches nothing", func(t *testing.T) {
+		require.Equal(t, []string{
+			"Some repeats something that can match without consuming input, it would never stop",
+			"Many repeats something that can match without consuming input, it would never stop",
+		}, lintMessages(Lint(Seq(Some(Chars("a", 0)), Many(Seq(Maybe("b"), Not("c")), Maybe(","))))))
+
+		require.Empty(t, Lint(Some(Chars("a", 0), ",")))
+	})
+
+	t.Run("nil reference", func(t *testing.T) {
+		var missing Parser
+		require.Equal(t, []string{"reference to a *Parser that is still nil"}, lintMessages(Lint(Seq("a", &missing))))
+	})
+
+	t.Run("left recursion", func(t *testing.T) {
+		var expr Parser
+		expr = NewParser("expr", Any(Seq(Maybe("+"), &expr, "-", NumberLit()), NumberLit()))
+		require.Equal(t, []string{"expr is left recursive, wrap it in LeftRec"}, lintMessages(Lint(&expr)))
+
+		var fixed Parser
+		fixed = LeftRec(Any(Seq(&fixed, "-", NumberLit()), NumberLit()))
+		require.Empty(t, Lint(&fixed))
+
+		var parens Parser
+		parens = Seq("(", Some(&parens), ")")
+		require.Empty(t, Lint(&parens))
+	})
+
+	t.Run("left recursion without a base case", func(t *testing.T) {
+		var expr Parser
+		expr = LeftRec(Seq(&expr, "-", "1"))
+		require.Equal(t, []string{"rule has no way to match without recursing, it needs a non recursive alternative"},
+			lintMessages(Lint(&expr)))
+
+		var indirect, term Parser
+		indirect = LeftRec(Any(Seq(&term, "-", "1"), Seq("(", &indirect, ")")))
+		term = Seq(Maybe("+"), &indirect)
+		require.Empty(t, Lint(&indirect))
+	})
+
+	t.Run("unused rules", func(t *testing.T) {
+		used := NewParser("used", Exact("a"))
+		unused := NewParser("unused", Exact("b"))
+		require.Equal(t, []string{"unused is never used"}, lintMessages(Lint(Seq(used, "c"), used, unused)))
+	})
+
+	t.Run("undescribed parsers", func(t *testing.T) {
+		var custom Parser = func(ps *State, node *Result) {}
+		require.Equal(t, []string{
+			"parser has no description, call RecordDescriptions before building it",
+		}, lintMessages(Lint(custom)))
+	})
+}
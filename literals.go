@@ -11,10 +11,13 @@ import (
 //  - escaped characters, eg \" or \n
 //  - unicode sequences, eg \uBEEF
 func StringLit(allowedQuotes string) Parser {
-	return NewParser("string literal", func(ps *State, node *Result) {
+	return describe(NewParser("string literal", func(ps *State, node *Result) {
 		ps.WS(ps)
+		if ps.Errored() {
+			return
+		}
 
-		if !stringContainsByte(allowedQuotes, ps.Input[ps.Pos]) {
+		if !ps.has(ps.Pos) || !stringContainsByte(allowedQuotes, ps.Input[ps.Pos]) {
 			ps.ErrorHere(allowedQuotes)
 			return
 		}
@@ -22,13 +25,12 @@ func StringLit(allowedQuotes string) Parser {
 
 		var end = ps.Pos + 1
 
-		inputLen := len(ps.Input)
 		var buf *bytes.Buffer
 
-		for end < inputLen {
+		for ps.has(end) {
 			switch ps.Input[end] {
 			case '\\':
-				if end+1 >= inputLen {
+				if !ps.has(end + 1) {
 					ps.ErrorHere(string(quote))
 					return
 				}
@@ -39,16 +41,14 @@ func StringLit(allowedQuotes string) Parser {
 
 				c := ps.Input[end+1]
 				if c == 'u' {
-					if end+6 >= inputLen {
-						ps.Error.expected = "[a-f0-9]{4}"
-						ps.Error.pos = end + 2
+					if !ps.has(end + 6) {
+						ps.ErrorAt(end+2, "[a-f0-9]{4}")
 						return
 					}
 
 					r, ok := unhex(ps.Input[end+2 : end+6])
 					if !ok {
-						ps.Error.expected = "[a-f0-9]"
-						ps.Error.pos = end + 2
+						ps.ErrorAt(end+2, "[a-f0-9]")
 						return
 					}
 					buf.WriteRune(r)
@@ -58,8 +58,10 @@ func StringLit(allowedQuotes string) Parser {
 					end += 2
 				}
 			case quote:
+				node.Start = ps.Offset + ps.Pos
+				node.End = ps.Offset + end + 1
 				if buf == nil {
-					node.Token = ps.Input[ps.Pos+1 : end]
+					node.Token = ps.token(ps.Pos+1, end)
 					ps.Pos = end + 1
 					return
 				}
@@ -67,6 +69,9 @@ func StringLit(allowedQuotes string) Parser {
 				node.Token = buf.String()
 				return
 			default:
+				if ps.Input[end] >= utf8.RuneSelf && !utf8.FullRuneInString(ps.Input[end:]) {
+					ps.fill(end + utf8.UTFMax)
+				}
 				if buf == nil {
 					if ps.Input[end] < 127 {
 						end++
@@ -83,43 +88,47 @@ func StringLit(allowedQuotes string) Parser {
 		}
 
 		ps.ErrorHere(string(quote))
+	}), func() *description {
+		return &description{kind: namedKind, text: "string literal", first: allowedQuotes, expected: []string{allowedQuotes}}
 	})
 }
 
 // NumberLit matches a floating point or integer number and returns it as a int64 or float64 in .Result
 func NumberLit() Parser {
-	return NewParser("number literal", func(ps *State, node *Result) {
+	return describe(NewParser("number literal", func(ps *State, node *Result) {
 		ps.WS(ps)
+		if ps.Errored() {
+			return
+		}
 		end := ps.Pos
 		float := false
-		inputLen := len(ps.Input)
 
-		if end < inputLen && (ps.Input[end] == '-' || ps.Input[end] == '+') {
+		if ps.has(end) && (ps.Input[end] == '-' || ps.Input[end] == '+') {
 			end++
 		}
 
-		for end < inputLen && ps.Input[end] >= '0' && ps.Input[end] <= '9' {
+		for ps.has(end) && ps.Input[end] >= '0' && ps.Input[end] <= '9' {
 			end++
 		}
 
-		if end < inputLen && ps.Input[end] == '.' {
+		if ps.has(end) && ps.Input[end] == '.' {
 			float = true
 			end++
 		}
 
-		for end < inputLen && ps.Input[end] >= '0' && ps.Input[end] <= '9' {
+		for ps.has(end) && ps.Input[end] >= '0' && ps.Input[end] <= '9' {
 			end++
 		}
 
-		if end < inputLen && (ps.Input[end] == 'e' || ps.Input[end] == 'E') {
+		if ps.has(end) && (ps.Input[end] == 'e' || ps.Input[end] == 'E') {
 			end++
 			float = true
 
-			if end < inputLen && (ps.Input[end] == '-' || ps.Input[end] == '+') {
+			if ps.has(end) && (ps.Input[end] == '-' || ps.Input[end] == '+') {
 				end++
 			}
 
-			for end < inputLen && ps.Input[end] >= '0' && ps.Input[end] <= '9' {
+			for ps.has(end) && ps.Input[end] >= '0' && ps.Input[end] <= '9' {
 				end++
 			}
 		}
@@ -139,7 +148,11 @@ func NumberLit() Parser {
 			ps.ErrorHere("number")
 			return
 		}
+		node.Start = ps.Offset + ps.Pos
+		node.End = ps.Offset + end
 		ps.Pos = end
+	}), func() *description {
+		return &description{kind: namedKind, text: "number literal", first: "+-.0123456789", expected: []string{"number"}}
 	})
 }
 
@@ -71,13 +71,15 @@ func TestStringLit(t *testing.T) {
 
 	t.Run("test invalid escaped unicode", func(t *testing.T) {
 		_, p := runParser(`"hello \ucake"`, parser)
-		require.Equal(t, "offset 9: expected [a-f0-9]", p.Error.Error())
+		require.Equal(t, "[a-f0-9]", p.Error.expected)
+		require.Equal(t, 9, p.Error.Pos())
 		require.Equal(t, 0, p.Pos)
 	})
 
 	t.Run("test incomplete escaped unicode", func(t *testing.T) {
 		_, p := runParser(`"hello \uca"`, parser)
-		require.Equal(t, "offset 9: expected [a-f0-9]{4}", p.Error.Error())
+		require.Equal(t, "[a-f0-9]{4}", p.Error.expected)
+		require.Equal(t, 9, p.Error.Pos())
 		require.Equal(t, 0, p.Pos)
 	})
 }
@@ -164,13 +166,15 @@ func TestNumberLit(t *testing.T) {
 
 	t.Run("non matching string", func(t *testing.T) {
 		_, p := runParser("foo", parser)
-		require.Equal(t, "offset 0: expected number", p.Error.Error())
+		require.Equal(t, "number", p.Error.expected)
+		require.Equal(t, 0, p.Error.Pos())
 		require.Equal(t, 0, p.Pos)
 	})
 
 	t.Run("invalid number", func(t *testing.T) {
 		_, p := runParser("-.", parser)
-		require.Equal(t, "offset 0: expected number", p.Error.Error())
+		require.Equal(t, "number", p.Error.expected)
+		require.Equal(t, 0, p.Error.Pos())
 		require.Equal(t, 0, p.Pos)
 	})
 }
@@ -0,0 +1,98 @@
+package goparsify
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+var memoIDs int64
+
+type memoKey struct {
+	id  int64
+	pos int
+	// the outcome of a parser also depends on the whitespace rules and whether it is behind a cut
+	ws  uintptr
+	cut bool
+}
+
+type memoEntry struct {
+	result Result
+	end    int
+	cut    int
+	err    Error
+	// recovered is what the parser recovered from, which has to be found again each time it is replayed
+	recovered []Error
+	// ws keeps the whitespace parser in the key alive, so its address can't be reused by a different one
+	ws VoidParser
+}
+
+// packrat is what Memo and LeftRec keep in a State, it is only allocated by parses that use them
+type packrat struct {
+	// results cached by Memo
+	memo map[memoKey]memoEntry
+	// left recursive matches being grown by LeftRec
+	growing map[memoKey]*seed
+}
+
+func (s *State) tables() *packrat {
+	if s.packrat == nil {
+		s.packrat = &packrat{}
+	}
+	return s.packrat
+}
+
+// wsID is the address of the closure behind ws, which is different for every WhitespaceWith even though they all
+// share the same code
+func wsID(ws VoidParser) uintptr {
+	return *(*uintptr)(unsafe.Pointer(&ws))
+}
+
+// Memo caches the outcome of parser at each position in the State, so no matter how many times
+// the grammar backtracks over it the parser will only run once per position. This is packrat
+// parsing, it trades memory for linear time on grammars that would otherwise retry the same
+// parser at the same offset over and over, eg an Any whose alternatives share a long prefix.
+//
+// Cached results are shared between every match so they must not be modified in place.
+func Memo(parser Parserish) Parser {
+	p := Parsify(parser)
+	id := atomic.AddInt64(&memoIDs, 1)
+
+	return describeAs(NewParser("Memo()", func(ps *State, node *Result) {
+		pos := ps.Offset + ps.Pos
+		key := memoKey{id: id, pos: pos, ws: wsID(ps.WS), cut: ps.Cut > pos}
+
+		tables := ps.tables()
+		if entry, ok := tables.memo[key]; ok {
+			ps.rewind(entry.end)
+			if entry.cut > ps.Cut {
+				ps.Cut = entry.cut
+			}
+			if entry.err.expected != "" {
+				ps.Error = entry.err
+				ps.expect(entry.err.pos, entry.err.expected)
+				return
+			}
+			*node = entry.result
+			ps.Recovered = append(ps.Recovered, entry.recovered...)
+			return
+		}
+
+		recovered := len(ps.Recovered)
+		p(ps, node)
+
+		if tables.memo == nil {
+			tables.memo = map[memoKey]memoEntry{}
+		}
+		entry := memoEntry{end: ps.Offset + ps.Pos, cut: ps.Cut, ws: ps.WS}
+		if ps.Errored() {
+			entry.err = ps.Error
+		} else {
+			entry.result = *node
+			if len(ps.Recovered) > recovered {
+				entry.recovered = append([]Error(nil), ps.Recovered[recovered:]...)
+			}
+		}
+		tables.memo[key] = entry
+		ps.Arena.keep()
+	}), p)
+}
@@ -0,0 +1,99 @@
+package goparsify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func countCalls(parser Parserish, calls *int) Parser {
+	p := Parsify(parser)
+	return func(ps *State, node *Result) {
+		*calls++
+		p(ps, node)
+	}
+}
+
+func TestMemo(t *testing.T) {
+	t.Run("runs once per position", func(t *testing.T) {
+		calls := 0
+		word := Memo(countCalls(Chars("a-z"), &calls))
+		parser := Any(Seq(word, "x"), Seq(word, "y"), Seq(word, "z"))
+
+		node, ps := runParser("hello z", parser)
+		require.False(t, ps.Errored())
+		require.Equal(t, "hello", node.Child[0].Token)
+		require.Equal(t, "z", node.Child[1].Token)
+		require.Equal(t, 7, ps.Pos)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("caches failures", func(t *testing.T) {
+		calls := 0
+		word := Memo(countCalls(Chars("a-z"), &calls))
+		parser := Any(Seq(word, "x"), Seq(word, "y"))
+
+		_, ps := runParser("123", parser)
+		require.Equal(t, "a-z", ps.Error.expected)
+		require.Equal(t, 0, ps.Error.Pos())
+		require.Equal(t, 0, ps.Pos)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("caches each position", func(t *testing.T) {
+		calls := 0
+		word := Memo(countCalls(Chars("a-z"), &calls))
+
+		node, ps := runParser("a b c", Some(Any(Seq(word, "!"), word)))
+		require.False(t, ps.Errored())
+		assertSequence(t, node, "a", "b", "c")
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("replays cuts", func(t *testing.T) {
+		calls := 0
+		open := Memo(countCalls(Seq("<", Cut()), &calls))
+
+		ps := NewState("<c>")
+		open(ps, TrashResult)
+		require.Equal(t, 1, ps.Cut)
+
+		ps.Pos = 0
+		ps.Cut = 0
+		open(ps, TrashResult)
+		require.Equal(t, 1, ps.Pos)
+		require.Equal(t, 1, ps.Cut)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("respects whitespace", func(t *testing.T) {
+		calls := 0
+		word := Memo(countCalls("hello", &calls))
+		parser := Any(Seq(NoAutoWS(word), "x"), Seq(word, "y"))
+
+		node, ps := runParser(" hello y", parser)
+		require.False(t, ps.Errored())
+		require.Equal(t, "hello", node.Child[0].Token)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("tells whitespace with the same code apart", func(t *testing.T) {
+		calls := 0
+		word := Memo(countCalls("hello", &calls))
+		hashes := WhitespaceWith("#")
+		slashes := WhitespaceWith("//")
+
+		ps := NewState("#\nhello")
+		ps.WS = slashes
+		word(ps, TrashResult)
+		require.True(t, ps.Errored())
+
+		ps.Pos = 0
+		ps.Recover()
+		ps.WS = hashes
+		word(ps, TrashResult)
+		require.False(t, ps.Errored())
+		require.Equal(t, 7, ps.Pos)
+		require.Equal(t, 2, calls)
+	})
+}
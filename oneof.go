@@ -0,0 +1,124 @@
+package goparsify
+
+import (
+	"fmt"
+	"sort"
+)
+
+// trie is built from a list of words, each node knows which word ends there
+type trie struct {
+	next map[byte]*trie
+	// word is the index of the word that ends here, or -1
+	word int
+}
+
+func newTrie(words []string) *trie {
+	root := &trie{word: -1}
+	for i, word := range words {
+		t := root
+		for j := 0; j < len(word); j++ {
+			if t.next == nil {
+				t.next = map[byte]*trie{}
+			}
+			next, ok := t.next[word[j]]
+			if !ok {
+				next = &trie{word: -1}
+				t.next[word[j]] = next
+			}
+			t = next
+		}
+		if t.word == -1 {
+			t.word = i
+		}
+	}
+	return root
+}
+
+// longest finds the longest word that s starts with, returning its index and length, or -1 when there isn't one
+func (t *trie) longest(s string) (word int, length int) {
+	word = t.word
+	for i := 0; i < len(s); i++ {
+		if t = t.next[s[i]]; t == nil {
+			break
+		}
+		if t.word != -1 {
+			word, length = t.word, i+1
+		}
+	}
+	return word, length
+}
+
+// OneOf matches the longest of the words that the input starts with, and stores it in .Token. Unlike Any the order
+// doesn't matter, OneOf("<", "<=", "<<=") matches all of "<<=" and not just the "<".
+// It doesn't check for the end of a word, so OneOf("in") matches the start of "index", use Keyword for that.
+func OneOf(words ...string) Parser {
+	return oneOfImpl(words, nil)
+}
+
+// Symbols is OneOf with a value for each word, which is set as the .Result when it matches like Bind, eg:
+//  Symbols(map[string]interface{}{"+": add, "-": sub, "*": mul})
+func Symbols(symbols map[string]interface{}) Parser {
+	words := make([]string, 0, len(symbols))
+	for word := range symbols {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	values := make([]interface{}, len(words))
+	for i, word := range words {
+		values[i] = symbols[word]
+	}
+	return oneOfImpl(words, values)
+}
+
+func oneOfImpl(words []string, values []interface{}) Parser {
+	if len(words) == 0 {
+		panic(fmt.Errorf("OneOf needs at least one word"))
+	}
+	root := newTrie(words)
+	maxLen := 0
+	expected := make([]string, len(words))
+	for i, word := range words {
+		if len(word) > maxLen {
+			maxLen = len(word)
+		}
+		expected[i] = "'" + word + "'"
+	}
+
+	return describe(NewParser("OneOf()", func(ps *State, node *Result) {
+		ps.WS(ps)
+		if ps.Errored() {
+			return
+		}
+		if len(ps.Input)-ps.Pos < maxLen {
+			ps.fill(ps.Pos + maxLen)
+		}
+
+		word, length := root.longest(ps.Get())
+		if word == -1 {
+			// every word is expected, so they are all listed in the error
+			for _, e := range expected {
+				ps.ErrorHere(e)
+			}
+			return
+		}
+
+		node.Start = ps.Offset + ps.Pos
+		ps.Advance(length)
+		node.End = ps.Offset + ps.Pos
+		node.Token = words[word]
+		if values != nil {
+			node.Result = values[word]
+		}
+	}), func() *description {
+		// longest first, which is the order Any would need them in to match the same way
+		sorted := append([]string{}, words...)
+		sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+		d := &description{kind: anyKind, expected: expected}
+		for _, word := range sorted {
+			d.children = append(d.children, &description{kind: exactKind, text: word})
+		}
+		return d
+	})
+}
@@ -0,0 +1,79 @@
+package goparsify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOneOf(t *testing.T) {
+	p := OneOf("<", "<<=", "<=", "<<", "=")
+
+	t.Run("takes the longest match", func(t *testing.T) {
+		node, ps := runParser("<<= 1", p)
+		require.False(t, ps.Errored())
+		require.Equal(t, "<<=", node.Token)
+		require.Equal(t, 3, ps.Pos)
+
+		node, ps = runParser("<<1", p)
+		require.Equal(t, "<<", node.Token)
+		require.Equal(t, 2, ps.Pos)
+
+		node, ps = runParser("<1", p)
+		require.Equal(t, "<", node.Token)
+		require.Equal(t, Result{Token: "<", Start: 0, End: 1}, node)
+	})
+
+	t.Run("backs off to a shorter word", func(t *testing.T) {
+		node, ps := runParser("<<<", OneOf("<", "<<<<"))
+		require.False(t, ps.Errored())
+		require.Equal(t, "<", node.Token)
+	})
+
+	t.Run("skips whitespace", func(t *testing.T) {
+		node, ps := runParser("  <=", p)
+		require.Equal(t, "<=", node.Token)
+		require.Equal(t, 2, node.Start)
+		require.Equal(t, 4, ps.Pos)
+	})
+
+	t.Run("expects every word", func(t *testing.T) {
+		_, err := Run(OneOf("if", "else"), "for")
+		require.Equal(t, []string{"'if'", "'else'"}, err.(*Error).Expected())
+	})
+
+	t.Run("matches at the end of the input", func(t *testing.T) {
+		node, ps := runParser("xab", Seq("x", OneOf("abc", "ab")))
+		require.False(t, ps.Errored())
+		require.Equal(t, "ab", node.Child[1].Token)
+	})
+
+	t.Run("needs words", func(t *testing.T) {
+		require.Panics(t, func() { OneOf() })
+	})
+}
+
+func TestSymbols(t *testing.T) {
+	p := Symbols(map[string]interface{}{"+": 1, "++": 2, "-": 3})
+
+	t.Run("binds the value", func(t *testing.T) {
+		node, _ := runParser("++", p)
+		require.Equal(t, "++", node.Token)
+		require.Equal(t, 2, node.Result)
+
+		node, _ = runParser("-", p)
+		require.Equal(t, 3, node.Result)
+	})
+
+	t.Run("expects every word in order", func(t *testing.T) {
+		_, err := Run(p, "*")
+		require.Equal(t, []string{"'+'", "'++'", "'-'"}, err.(*Error).Expected())
+	})
+
+	t.Run("describes itself longest first", func(t *testing.T) {
+		defer recordDescriptions()()
+		p := Symbols(map[string]interface{}{"+": 1, "++": 2, "-": 3})
+		require.Equal(t, "grammar ::= \"++\" | \"+\" | \"-\"\n", Describe(p).EBNF())
+		require.Empty(t, Lint(p))
+	})
+}
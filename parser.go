@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -24,7 +25,7 @@ func (p Parser) Map(f func(n *Result)) Parser {
 type VoidParser func(*State)
 
 // Parserish types are any type that can be turned into a Parser by Parsify
-// These currently include *Parser and string literals.
+// These currently include *Parser, TypedParser and string literals.
 //
 // This makes recursive grammars cleaner and allows string literals to be used directly in most contexts.
 // eg, matching balanced paren:
@@ -48,20 +49,33 @@ func Parsify(p Parserish) Parser {
 		return p
 	case *Parser:
 		// Todo: Maybe capture this stack and on nil show it? Is there a good error library to do this?
-		return func(ptr *State, node *Result) {
+		return describe(func(ptr *State, node *Result) {
+			if !ptr.enter() {
+				return
+			}
 			(*p)(ptr, node)
-		}
+			ptr.leave()
+		}, func() *description {
+			return &description{kind: refKind, ref: p}
+		})
 	case string:
 		return Exact(p)
 	case func(*State):
 		return func(ptr *State, node *Result) {
 			p(ptr)
 		}
+	case untyped:
+		return p.Untyped()
 	default:
 		panic(fmt.Errorf("cant turn a `%T` into a parser", p))
 	}
 }
 
+// untyped is implemented by TypedParser, which can't be named here without generics
+type untyped interface {
+	Untyped() Parser
+}
+
 // ParsifyAll calls Parsify on all parsers
 func ParsifyAll(parsers ...Parserish) []Parser {
 	ret := make([]Parser, len(parsers))
@@ -72,81 +86,242 @@ func ParsifyAll(parsers ...Parserish) []Parser {
 }
 
 // Run applies some input to a parser and returns the result, failing if the input isnt fully consumed.
-// It is a convenience method for the most common way to invoke a parser.
+// It is a convenience method for the most common way to invoke a parser. If Recover skipped over any
+// errors they are all returned in an ErrorList.
 func Run(parser Parserish, input string, ws ...VoidParser) (result interface{}, err error) {
-	p := Parsify(parser)
-	ps := NewState(input)
+	return run(Parsify(parser), NewState(input), ws)
+}
+
+// RunState is Run with a State that has already been set up, eg with an Arena
+func RunState(parser Parserish, ps *State, ws ...VoidParser) (result interface{}, err error) {
+	return run(Parsify(parser), ps, ws)
+}
+
+func run(p Parser, ps *State, ws []VoidParser) (result interface{}, err error) {
 	if len(ws) > 0 {
 		ps.WS = ws[0]
 	}
 
 	ret := Result{}
 	p(ps, &ret)
-	ps.WS(ps)
+	if abort := ps.aborted(); abort != nil {
+		return nil, abort
+	}
+	if !ps.Errored() {
+		ps.WS(ps)
+	}
+
+	if readErr := ps.readError(); readErr != nil {
+		return ret.Result, readErr
+	}
 
-	if ps.Error.expected != "" {
-		return ret.Result, &ps.Error
+	if ps.Errored() {
+		ps.Error = ps.furthestError(-1)
+		err = &ps.Error
+	} else if ps.has(ps.Pos) {
+		err = UnparsedInputError{ps.Offset + ps.Pos, ps.detach(ps.Input), ps.discardedInput()}
 	}
 
-	if ps.Get() != "" {
-		return ret.Result, UnparsedInputError{ps.Get()}
+	if len(ps.Recovered) > 0 {
+		errs := make(ErrorList, 0, len(ps.Recovered)+1)
+		for i := range ps.Recovered {
+			errs = append(errs, &ps.Recovered[i])
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return ret.Result, errs
 	}
 
-	return ret.Result, nil
+	return ret.Result, err
 }
 
 // Cut prevents backtracking beyond this point. Usually used after keywords when you
 // are sure this is the correct path. Improves performance and error reporting.
 func Cut() Parser {
-	return func(ps *State, node *Result) {
-		ps.Cut = ps.Pos
-	}
+	return describe(func(ps *State, node *Result) {
+		ps.Cut = ps.Offset + ps.Pos
+		ps.discard()
+	}, func() *description {
+		return &description{kind: emptyKind}
+	})
 }
 
 // Regex returns a match if the regex successfully matches
 func Regex(pattern string) Parser {
 	re := regexp.MustCompile("^" + pattern)
-	return NewParser(pattern, func(ps *State, node *Result) {
+	return describe(NewParser(pattern, func(ps *State, node *Result) {
 		ps.WS(ps)
-		if match := re.FindString(ps.Get()); match != "" {
+		if ps.Errored() {
+			return
+		}
+		var match string
+		if ps.stream == nil {
+			match = re.FindString(ps.Get())
+		} else if loc := re.FindReaderIndex(&runeReader{ps: ps, pos: ps.Pos}); loc != nil {
+			match = ps.Input[ps.Pos : ps.Pos+loc[1]]
+		}
+		if match != "" {
+			node.Token = ps.token(ps.Pos, ps.Pos+len(match))
+			node.Start = ps.Offset + ps.Pos
 			ps.Advance(len(match))
-			node.Token = match
+			node.End = ps.Offset + ps.Pos
 			return
 		}
 		ps.ErrorHere(pattern)
+	}), func() *description {
+		return &description{kind: regexKind, text: pattern}
 	})
 }
 
 // Exact will fully match the exact string supplied, or error. The match will be stored in .Token
 func Exact(match string) Parser {
+	expected := "'" + match + "'"
 	if len(match) == 1 {
 		matchByte := match[0]
-		return NewParser(match, func(ps *State, node *Result) {
+		return describe(NewParser(match, func(ps *State, node *Result) {
 			ps.WS(ps)
-			if ps.Pos >= len(ps.Input) || ps.Input[ps.Pos] != matchByte {
-				ps.ErrorHere(match)
+			if ps.Errored() {
+				return
+			}
+			if !ps.has(ps.Pos) || ps.Input[ps.Pos] != matchByte {
+				ps.ErrorHere(expected)
 				return
 			}
 
+			node.Start = ps.Offset + ps.Pos
 			ps.Advance(1)
+			node.End = ps.Offset + ps.Pos
 
 			node.Token = match
+		}), func() *description {
+			return &description{kind: exactKind, text: match}
 		})
 	}
 
-	return NewParser(match, func(ps *State, node *Result) {
+	return describe(NewParser(match, func(ps *State, node *Result) {
 		ps.WS(ps)
+		if ps.Errored() {
+			return
+		}
+		if len(ps.Input)-ps.Pos < len(match) {
+			ps.fill(ps.Pos + len(match))
+		}
 		if !strings.HasPrefix(ps.Get(), match) {
-			ps.ErrorHere(match)
+			ps.ErrorHere(expected)
 			return
 		}
 
+		node.Start = ps.Offset + ps.Pos
 		ps.Advance(len(match))
+		node.End = ps.Offset + ps.Pos
 
 		node.Token = match
+	}), func() *description {
+		return &description{kind: exactKind, text: match}
 	})
 }
 
+// ExactFold is Exact ignoring case, using unicode simple case folding, eg ExactFold("select") matches SELECT.
+// The Token is the text as written in the input.
+func ExactFold(match string) Parser {
+	expected := "'" + match + "'"
+	if len(match) == 1 {
+		lower, upper := match[0], match[0]
+		if 'a' <= lower && lower <= 'z' {
+			upper -= 'a' - 'A'
+		} else if 'A' <= upper && upper <= 'Z' {
+			lower += 'a' - 'A'
+		}
+		return describe(NewParser(match, func(ps *State, node *Result) {
+			ps.WS(ps)
+			if ps.Errored() {
+				return
+			}
+			if !ps.has(ps.Pos) {
+				ps.ErrorHere(expected)
+				return
+			}
+
+			matched := 1
+			if c := ps.Input[ps.Pos]; c != lower && c != upper {
+				// a few non ascii runes fold to ascii letters, eg the kelvin sign
+				if c < utf8.RuneSelf {
+					ps.ErrorHere(expected)
+					return
+				}
+				ps.fill(ps.Pos + utf8.UTFMax)
+				if matched = hasPrefixFold(ps.Get(), match); matched == -1 {
+					ps.ErrorHere(expected)
+					return
+				}
+			}
+
+			node.Token = ps.token(ps.Pos, ps.Pos+matched)
+			node.Start = ps.Offset + ps.Pos
+			ps.Advance(matched)
+			node.End = ps.Offset + ps.Pos
+		}), func() *description {
+			return &description{kind: exactKind, text: match, fold: true}
+		})
+	}
+
+	return describe(NewParser(match, func(ps *State, node *Result) {
+		ps.WS(ps)
+		if ps.Errored() {
+			return
+		}
+		// folding can change the length of a rune, but never by more than it has
+		if len(ps.Input)-ps.Pos < len(match)*utf8.UTFMax {
+			ps.fill(ps.Pos + len(match)*utf8.UTFMax)
+		}
+		matched := hasPrefixFold(ps.Get(), match)
+		if matched == -1 {
+			ps.ErrorHere(expected)
+			return
+		}
+
+		node.Token = ps.token(ps.Pos, ps.Pos+matched)
+		node.Start = ps.Offset + ps.Pos
+		ps.Advance(matched)
+		node.End = ps.Offset + ps.Pos
+	}), func() *description {
+		return &description{kind: exactKind, text: match, fold: true}
+	})
+}
+
+// hasPrefixFold is strings.HasPrefix under unicode simple case folding. It returns how many bytes of s matched,
+// which can differ from len(prefix), or -1 if it doesn't match.
+func hasPrefixFold(s, prefix string) int {
+	i := 0
+	for _, pr := range prefix {
+		if i >= len(s) {
+			return -1
+		}
+		sr, w := utf8.DecodeRuneInString(s[i:])
+		if !equalFoldRune(sr, pr) {
+			return -1
+		}
+		i += w
+	}
+	return i
+}
+
+func equalFoldRune(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	if a < utf8.RuneSelf && b < utf8.RuneSelf {
+		return 'A' <= a && a <= 'Z' && a+'a'-'A' == b || 'A' <= b && b <= 'Z' && b+'a'-'A' == a
+	}
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
+
 func parseRepetition(defaultMin, defaultMax int, repetition ...int) (min int, max int) {
 	min = defaultMin
 	max = defaultMax
@@ -197,35 +372,47 @@ func parseMatcher(matcher string) (alphabet string, ranges [][]rune) {
 //  - min and max: Chars("a-z0-9", 4, 6) will match 4-6 lowercase alphanumeric characters
 // the above can be combined in any order
 func Chars(matcher string, repetition ...int) Parser {
-	return NewParser("["+matcher+"]", charsImpl(matcher, false, repetition...))
+	return describe(NewParser("["+matcher+"]", charsImpl(matcher, false, false, repetition...)), charsDescription(matcher, false, false, repetition))
+}
+
+// CharsFold is Chars ignoring case, using unicode simple case folding, eg CharsFold("a-f0-9") matches hex in either case
+func CharsFold(matcher string, repetition ...int) Parser {
+	return describe(NewParser("["+matcher+"]", charsImpl(matcher, false, true, repetition...)), charsDescription(matcher, false, true, repetition))
 }
 
 // NotChars accepts the full range of input from Chars, but it will stop when any
 // character matches. If you need to match until you see a sequence use Until instead
 func NotChars(matcher string, repetition ...int) Parser {
-	return NewParser("!["+matcher+"]", charsImpl(matcher, true, repetition...))
+	return describe(NewParser("!["+matcher+"]", charsImpl(matcher, true, false, repetition...)), charsDescription(matcher, true, false, repetition))
 }
 
-func charsImpl(matcher string, stopOn bool, repetition ...int) Parser {
+func charsImpl(matcher string, stopOn bool, fold bool, repetition ...int) Parser {
 	min, max := parseRepetition(1, -1, repetition...)
 	alphabet, ranges := parseMatcher(matcher)
 
 	return func(ps *State, node *Result) {
 		ps.WS(ps)
+		if ps.Errored() {
+			return
+		}
 		matched := 0
-		for ps.Pos+matched < len(ps.Input) {
+		for ps.has(ps.Pos + matched) {
 			if max != -1 && matched >= max {
 				break
 			}
 
-			r, w := utf8.DecodeRuneInString(ps.Input[ps.Pos+matched:])
+			r, w := rune(ps.Input[ps.Pos+matched]), 1
+			if r >= utf8.RuneSelf {
+				if !utf8.FullRuneInString(ps.Input[ps.Pos+matched:]) {
+					ps.fill(ps.Pos + matched + utf8.UTFMax)
+				}
+				r, w = utf8.DecodeRuneInString(ps.Input[ps.Pos+matched:])
+			}
 
-			anyMatched := strings.ContainsRune(alphabet, r)
-			if !anyMatched {
-				for _, rng := range ranges {
-					if r >= rng[0] && r <= rng[1] {
-						anyMatched = true
-					}
+			anyMatched := matchesRune(alphabet, ranges, r)
+			if !anyMatched && fold {
+				for f := unicode.SimpleFold(r); f != r && !anyMatched; f = unicode.SimpleFold(f) {
+					anyMatched = matchesRune(alphabet, ranges, f)
 				}
 			}
 
@@ -241,19 +428,42 @@ func charsImpl(matcher string, stopOn bool, repetition ...int) Parser {
 			return
 		}
 
-		node.Token = ps.Input[ps.Pos : ps.Pos+matched]
+		node.Token = ps.token(ps.Pos, ps.Pos+matched)
+		node.Start = ps.Offset + ps.Pos
 		ps.Advance(matched)
+		node.End = ps.Offset + ps.Pos
+	}
+}
+
+func matchesRune(alphabet string, ranges [][]rune, r rune) bool {
+	if strings.ContainsRune(alphabet, r) {
+		return true
 	}
+	for _, rng := range ranges {
+		if r >= rng[0] && r <= rng[1] {
+			return true
+		}
+	}
+	return false
 }
 
 // Until will consume all input until one of the given terminator sequences is found. If you want to stop when seeing
 // single characters see NotChars instead
 func Until(terminators ...string) Parser {
+	longest := 0
+	for _, terminator := range terminators {
+		if len(terminator) > longest {
+			longest = len(terminator)
+		}
+	}
 
 	return NewParser("Until", func(ps *State, node *Result) {
 		startPos := ps.Pos
 	loop:
-		for ps.Pos < len(ps.Input) {
+		for ps.has(ps.Pos) {
+			if ps.Pos+longest > len(ps.Input) {
+				ps.fill(ps.Pos + longest)
+			}
 			for _, terminator := range terminators {
 				if ps.Pos+len(terminator) <= len(ps.Input) && ps.Input[ps.Pos:ps.Pos+len(terminator)] == terminator {
 					break loop
@@ -265,6 +475,8 @@ func Until(terminators ...string) Parser {
 		if ps.Pos == startPos {
 			ps.ErrorHere("something")
 		}
-		node.Token = ps.Input[startPos:ps.Pos]
+		node.Token = ps.token(startPos, ps.Pos)
+		node.Start = ps.Offset + startPos
+		node.End = ps.Offset + ps.Pos
 	})
 }
@@ -65,23 +65,66 @@ func TestExact(t *testing.T) {
 
 	t.Run("error", func(t *testing.T) {
 		_, ps := runParser("foobar", Exact("bar"))
-		require.Equal(t, "bar", ps.Error.expected)
+		require.Equal(t, "'bar'", ps.Error.expected)
 		require.Equal(t, 0, ps.Pos)
 	})
 
 	t.Run("error char", func(t *testing.T) {
 		_, ps := runParser("foobar", Exact("o"))
-		require.Equal(t, "o", ps.Error.expected)
+		require.Equal(t, "'o'", ps.Error.expected)
 		require.Equal(t, 0, ps.Pos)
 	})
 
 	t.Run("eof char", func(t *testing.T) {
 		_, ps := runParser("", Exact("o"))
-		require.Equal(t, "o", ps.Error.expected)
+		require.Equal(t, "'o'", ps.Error.expected)
 		require.Equal(t, 0, ps.Pos)
 	})
 }
 
+func TestExactFold(t *testing.T) {
+	t.Run("success string", func(t *testing.T) {
+		node, ps := runParser("SeLect *", ExactFold("select"))
+		require.Equal(t, "SeLect", node.Token)
+		require.Equal(t, " *", ps.Get())
+	})
+
+	t.Run("success char", func(t *testing.T) {
+		node, ps := runParser("Foo", ExactFold("f"))
+		require.Equal(t, "F", node.Token)
+		require.Equal(t, "oo", ps.Get())
+
+		node, _ = runParser("\u212A", ExactFold("k"))
+		require.Equal(t, "\u212A", node.Token)
+
+		node, _ = runParser("1", ExactFold("1"))
+		require.Equal(t, "1", node.Token)
+	})
+
+	t.Run("unicode", func(t *testing.T) {
+		node, ps := runParser("ΣΊΣΥΦΟΣ", ExactFold("σίσυφος"))
+		require.Equal(t, "ΣΊΣΥΦΟΣ", node.Token)
+		require.Equal(t, "", ps.Get())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, ps := runParser("selec", ExactFold("select"))
+		require.Equal(t, "'select'", ps.Error.expected)
+		require.Equal(t, 0, ps.Pos)
+	})
+
+	t.Run("error char", func(t *testing.T) {
+		_, ps := runParser("g", ExactFold("f"))
+		require.Equal(t, "'f'", ps.Error.expected)
+
+		_, ps = runParser("é", ExactFold("e"))
+		require.Equal(t, "'e'", ps.Error.expected)
+
+		_, ps = runParser("", ExactFold("e"))
+		require.Equal(t, "'e'", ps.Error.expected)
+	})
+}
+
 func TestChars(t *testing.T) {
 	t.Run("full match", func(t *testing.T) {
 		node, ps := runParser("foobar", Chars("a-z"))
@@ -120,7 +163,8 @@ func TestChars(t *testing.T) {
 
 	t.Run("no match", func(t *testing.T) {
 		_, ps := runParser("ffffff", Chars("0-9"))
-		require.Equal(t, "offset 0: expected 0-9", ps.Error.Error())
+		require.Equal(t, "0-9", ps.Error.expected)
+		require.Equal(t, 0, ps.Error.Pos())
 		require.Equal(t, 0, ps.Pos)
 	})
 
@@ -149,6 +193,31 @@ func TestChars(t *testing.T) {
 	})
 }
 
+func TestCharsFold(t *testing.T) {
+	t.Run("ranges and alphabets", func(t *testing.T) {
+		node, ps := runParser("DeadBeef01x", CharsFold("a-f0-9"))
+		require.Equal(t, "DeadBeef01", node.Token)
+		require.Equal(t, "x", ps.Get())
+
+		node, _ = runParser("XxyY", CharsFold("xy"))
+		require.Equal(t, "XxyY", node.Token)
+	})
+
+	t.Run("unicode", func(t *testing.T) {
+		node, _ := runParser("ÉéÈ", CharsFold("é"))
+		require.Equal(t, "Éé", node.Token)
+
+		node, _ = runParser("\u212Ak", CharsFold("K"))
+		require.Equal(t, "\u212Ak", node.Token)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, ps := runParser("ghi", CharsFold("a-f"))
+		require.Equal(t, "a-f", ps.Error.expected)
+		require.Equal(t, 0, ps.Pos)
+	})
+}
+
 func TestRegex(t *testing.T) {
 	t.Run("full match", func(t *testing.T) {
 		node, ps := runParser("hello", Regex("[a-z]*"))
@@ -166,13 +235,15 @@ func TestRegex(t *testing.T) {
 
 	t.Run("no match", func(t *testing.T) {
 		_, ps := runParser("1234", Regex("[a-z]*"))
-		require.Equal(t, "offset 0: expected [a-z]*", ps.Error.Error())
+		require.Equal(t, "[a-z]*", ps.Error.expected)
+		require.Equal(t, 0, ps.Error.Pos())
 		require.Equal(t, 0, ps.Pos)
 	})
 
 	t.Run("eof", func(t *testing.T) {
 		_, ps := runParser("", Regex("[a-z]*"))
-		require.Equal(t, "offset 0: expected [a-z]*", ps.Error.Error())
+		require.Equal(t, "[a-z]*", ps.Error.expected)
+		require.Equal(t, 0, ps.Error.Pos())
 		require.Equal(t, 0, ps.Pos)
 	})
 }
@@ -190,21 +261,22 @@ func TestParseString(t *testing.T) {
 		result, err := Run(Y, "hello world")
 		require.Equal(t, "hello", result)
 		require.Error(t, err)
-		require.Equal(t, "left unparsed: world", err.Error())
+		require.Equal(t, "1:7: left unparsed\nhello world\n      ^", err.Error())
 	})
 
 	t.Run("error", func(t *testing.T) {
 		result, err := Run(Y, "world")
 		require.Nil(t, result)
 		require.Error(t, err)
-		require.Equal(t, "offset 0: expected hello", err.Error())
+		require.Equal(t, "1:1: expected 'hello'\nworld\n^", err.Error())
 	})
 }
 
 func TestAutoWS(t *testing.T) {
 	t.Run("ws is not automatically consumed", func(t *testing.T) {
 		_, ps := runParser(" hello", NoAutoWS("hello"))
-		require.Equal(t, "offset 0: expected hello", ps.Error.Error())
+		require.Equal(t, "'hello'", ps.Error.expected)
+		require.Equal(t, 0, ps.Error.Pos())
 	})
 
 	t.Run("ws is can be explicitly consumed ", func(t *testing.T) {
@@ -13,3 +13,28 @@ func BenchmarkAny(b *testing.B) {
 		_, _ = Run(p, "help me")
 	}
 }
+
+func backtrackingExpr(memo func(Parserish) Parser) Parser {
+	var expr Parser
+	term := memo(Any(Seq("(", &expr, ")"), NumberLit()))
+	expr = Any(Seq(term, "+", &expr), Seq(term, "-", &expr), Seq(term, "*", &expr), term)
+	return expr
+}
+
+const nestedExpr = "((((((1))))))+((((((2))))))"
+
+func BenchmarkBacktracking(b *testing.B) {
+	p := backtrackingExpr(Parsify)
+
+	for i := 0; i < b.N; i++ {
+		_, _ = Run(p, nestedExpr)
+	}
+}
+
+func BenchmarkBacktrackingMemo(b *testing.B) {
+	p := backtrackingExpr(Memo)
+
+	for i := 0; i < b.N; i++ {
+		_, _ = Run(p, nestedExpr)
+	}
+}
@@ -0,0 +1,151 @@
+// Package pretty prints values back out using the same grammar that parses them. Documents are laid out with
+// Wadler's "A prettier printer": a Group is printed on one line if it fits, otherwise its Lines become newlines.
+package pretty
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// Doc is a document to be laid out by Render
+type Doc interface {
+	isDoc()
+}
+
+type text string
+type line struct{ flat string }
+type nest struct{ doc Doc }
+type group struct{ doc Doc }
+type concat []Doc
+
+func (text) isDoc()   {}
+func (line) isDoc()   {}
+func (nest) isDoc()   {}
+func (group) isDoc()  {}
+func (concat) isDoc() {}
+
+// Text is printed as is, it should not contain newlines
+func Text(s string) Doc { return text(s) }
+
+// Line is a newline, or a space when its group fits on one line
+func Line() Doc { return line{flat: " "} }
+
+// SoftLine is a newline, or nothing when its group fits on one line
+func SoftLine() Doc { return line{} }
+
+// Nest indents every newline inside doc one more level
+func Nest(doc Doc) Doc { return nest{doc} }
+
+// Group prints doc on one line if it fits, otherwise all of its Lines (but not those of nested groups) break
+func Group(doc Doc) Doc { return group{doc} }
+
+// Concat prints docs one after another
+func Concat(docs ...Doc) Doc { return concat(docs) }
+
+// Join prints docs with sep between each of them
+func Join(sep Doc, docs []Doc) Doc {
+	joined := make(concat, 0, len(docs)*2)
+	for i, doc := range docs {
+		if i > 0 {
+			joined = append(joined, sep)
+		}
+		joined = append(joined, doc)
+	}
+	return joined
+}
+
+type mode int
+
+const (
+	flat mode = iota
+	broken
+)
+
+type item struct {
+	level int
+	mode  mode
+	doc   Doc
+}
+
+// Render lays doc out to fit within width columns where it can, indenting each level of Nest with indent.
+func Render(doc Doc, width int, indent string) string {
+	buf := &bytes.Buffer{}
+	col := 0
+	// indentation is written with the next text so blank lines don't end in spaces
+	pending := ""
+	stack := []item{{0, broken, group{doc}}}
+
+	for len(stack) > 0 {
+		it := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch d := it.doc.(type) {
+		case text:
+			if d != "" {
+				buf.WriteString(pending)
+				pending = ""
+			}
+			buf.WriteString(string(d))
+			col += utf8.RuneCountInString(string(d))
+		case line:
+			if it.mode == flat {
+				if d.flat != "" {
+					buf.WriteString(pending)
+					pending = ""
+				}
+				buf.WriteString(d.flat)
+				col += len(d.flat)
+			} else {
+				buf.WriteByte('\n')
+				pending = strings.Repeat(indent, it.level)
+				col = len(pending)
+			}
+		case nest:
+			stack = append(stack, item{it.level + 1, it.mode, d.doc})
+		case group:
+			next := item{it.level, flat, d.doc}
+			if it.mode == broken && !fits(width-col, append(stack, next)) {
+				next.mode = broken
+			}
+			stack = append(stack, next)
+		case concat:
+			for i := len(d) - 1; i >= 0; i-- {
+				stack = append(stack, item{it.level, it.mode, d[i]})
+			}
+		}
+	}
+
+	return buf.String()
+}
+
+// fits checks whether the top of the stack fits in the remaining width, up to the next newline
+func fits(width int, stack []item) bool {
+	pending := append([]item(nil), stack...)
+	for width >= 0 {
+		if len(pending) == 0 {
+			return true
+		}
+		it := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		switch d := it.doc.(type) {
+		case text:
+			width -= utf8.RuneCountInString(string(d))
+		case line:
+			if it.mode == broken {
+				return true
+			}
+			width -= len(d.flat)
+		case nest:
+			pending = append(pending, item{it.level + 1, it.mode, d.doc})
+		case group:
+			pending = append(pending, item{it.level, it.mode, d.doc})
+		case concat:
+			for i := len(d) - 1; i >= 0; i-- {
+				pending = append(pending, item{it.level, it.mode, d[i]})
+			}
+		}
+	}
+	return false
+}
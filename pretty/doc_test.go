@@ -0,0 +1,39 @@
+package pretty
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	list := func(items ...string) Doc {
+		docs := make([]Doc, len(items))
+		for i, item := range items {
+			docs[i] = Text(item)
+		}
+		return Group(Concat(Text("["), Nest(Concat(SoftLine(), Join(Concat(Text(","), Line()), docs))), SoftLine(), Text("]")))
+	}
+
+	t.Run("fits on one line", func(t *testing.T) {
+		require.Equal(t, "[a, b, c]", Render(list("a", "b", "c"), 9, "  "))
+	})
+
+	t.Run("breaks when too wide", func(t *testing.T) {
+		require.Equal(t, "[\n  a,\n  b,\n  c\n]", Render(list("a", "b", "c"), 8, "  "))
+	})
+
+	t.Run("inner groups stay flat", func(t *testing.T) {
+		doc := Group(Concat(Text("["), Nest(Concat(SoftLine(), list("a", "b"), Text(","), Line(), list("c", "d"))), SoftLine(), Text("]")))
+		require.Equal(t, "[\n\t[a, b],\n\t[c, d]\n]", Render(doc, 10, "\t"))
+	})
+
+	t.Run("counts runes", func(t *testing.T) {
+		require.Equal(t, "[é, ü]", Render(list("é", "ü"), 6, " "))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		require.Equal(t, "[]", Render(list(), 2, " "))
+		require.Equal(t, "[\n\n]", Render(list(), 1, " "))
+	})
+}
@@ -0,0 +1,293 @@
+package pretty
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/goparsify"
+)
+
+// Syntax is a grammar that goes both ways, it parses text into a value and prints a value back into text.
+// Build them from the combinators in this package, the same way as goparsify parsers:
+//  var value Syntax
+//  items := Seq(SoftBreak, Some(Ref(&value), Seq(Exact(","), LineBreak))).Nest()
+//  list := Seq(Exact("["), items, SoftBreak, Exact("]")).Group()
+//  value = Any(NumberLit(), list)
+// Layout like LineBreak only affects printing, whitespace is skipped when parsing as usual.
+type Syntax struct {
+	parser goparsify.Parser
+	print  func(v interface{}, f *failure) (Doc, bool)
+}
+
+// failure is the innermost value that nothing could print, so the error can name it rather than what it was in
+type failure struct {
+	value interface{}
+	found bool
+}
+
+// Layout that parses nothing
+var (
+	// LineBreak prints a Line
+	LineBreak = Layout(Line())
+	// SoftBreak prints a SoftLine
+	SoftBreak = Layout(SoftLine())
+	// Space prints a space
+	Space = Layout(Text(" "))
+)
+
+// Parser is the goparsify parser for the syntax, so it can be used from ordinary grammars
+func (s Syntax) Parser() goparsify.Parser {
+	return s.parser
+}
+
+// Parse is goparsify.Run for the syntax
+func (s Syntax) Parse(input string, ws ...goparsify.VoidParser) (interface{}, error) {
+	return goparsify.Run(s.parser, input, ws...)
+}
+
+// Print turns a value into a Doc, failing if the value doesn't match the syntax. The error names the type of the
+// innermost value that couldn't be printed, eg the item of a list rather than the list.
+func (s Syntax) Print(v interface{}) (Doc, error) {
+	f := &failure{}
+	doc, ok := s.print(v, f)
+	if !ok {
+		if !f.found {
+			f.value = v
+		}
+		return nil, fmt.Errorf("cant print a `%T`", f.value)
+	}
+	return doc, nil
+}
+
+// Format prints a value and renders it, see Render
+func (s Syntax) Format(v interface{}, width int, indent string) (string, error) {
+	doc, err := s.Print(v)
+	if err != nil {
+		return "", err
+	}
+	return Render(doc, width, indent), nil
+}
+
+// Exact matches and prints a literal string, its value is nil
+func Exact(match string) Syntax {
+	return Syntax{
+		parser: goparsify.Exact(match),
+		print:  func(v interface{}, f *failure) (Doc, bool) { return Text(match), true },
+	}
+}
+
+// Layout parses nothing and prints doc, its value is nil
+func Layout(doc Doc) Syntax {
+	return Syntax{
+		parser: func(ps *goparsify.State, node *goparsify.Result) {},
+		print:  func(v interface{}, f *failure) (Doc, bool) { return doc, true },
+	}
+}
+
+// StringLit matches a double quoted string, its value is the unescaped string
+func StringLit() Syntax {
+	return Syntax{
+		parser: goparsify.StringLit(`"`).Map(func(n *goparsify.Result) { n.Result = n.Token }),
+		print: func(v interface{}, f *failure) (Doc, bool) {
+			s, ok := v.(string)
+			if !ok {
+				return nil, false
+			}
+			return Text(quote(s)), true
+		},
+	}
+}
+
+// quote escapes a string the way goparsify.StringLit unescapes it
+func quote(s string) string {
+	buf := &strings.Builder{}
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case r < ' ':
+			fmt.Fprintf(buf, `\u%04x`, r)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// NumberLit matches a number, its value is an int64 or float64. Printing also accepts an int.
+func NumberLit() Syntax {
+	return Syntax{
+		parser: goparsify.NumberLit(),
+		print: func(v interface{}, f *failure) (Doc, bool) {
+			switch v := v.(type) {
+			case int:
+				return Text(strconv.Itoa(v)), true
+			case int64:
+				return Text(strconv.FormatInt(v, 10)), true
+			case float64:
+				if math.IsInf(v, 0) || math.IsNaN(v) {
+					return nil, false
+				}
+				s := strconv.FormatFloat(v, 'g', -1, 64)
+				// without these it would come back as an int
+				if !strings.ContainsAny(s, ".e") {
+					s += ".0"
+				}
+				return Text(s), true
+			}
+			return nil, false
+		},
+	}
+}
+
+// Bind gives syntax the value val, and only prints values equal to it, eg Bind(Exact("true"), true)
+func Bind(syntax Syntax, val interface{}) Syntax {
+	return Syntax{
+		parser: goparsify.Bind(syntax.parser, val),
+		print: func(v interface{}, f *failure) (Doc, bool) {
+			// maps, slices and funcs can't be compared with !=
+			if !reflect.DeepEqual(v, val) {
+				return nil, false
+			}
+			return syntax.print(nil, f)
+		},
+	}
+}
+
+// Seq matches each syntax in order, its value is a []interface{} with the value of each
+func Seq(items ...Syntax) Syntax {
+	parsers := make([]goparsify.Parserish, len(items))
+	for i, item := range items {
+		parsers[i] = item.parser
+	}
+
+	return Syntax{
+		parser: goparsify.Seq(parsers...).Map(collect),
+		print: func(v interface{}, f *failure) (Doc, bool) {
+			values, ok := v.([]interface{})
+			if v == nil {
+				values = make([]interface{}, len(items))
+			} else if !ok || len(values) != len(items) {
+				return nil, false
+			}
+
+			docs := make(concat, len(items))
+			for i, item := range items {
+				if docs[i], ok = item.print(values[i], f); !ok {
+					return nil, false
+				}
+			}
+			return docs, true
+		},
+	}
+}
+
+// Any matches the first syntax that matches, and prints with the first one that can print the value
+func Any(alternatives ...Syntax) Syntax {
+	parsers := make([]goparsify.Parserish, len(alternatives))
+	for i, alternative := range alternatives {
+		parsers[i] = alternative.parser
+	}
+
+	return Syntax{
+		parser: goparsify.Any(parsers...),
+		print: func(v interface{}, f *failure) (Doc, bool) {
+			// an alternative may get part of the way before failing, which doesn't matter if another one prints it
+			saved := *f
+			for _, alternative := range alternatives {
+				if doc, ok := alternative.print(v, f); ok {
+					*f = saved
+					return doc, true
+				}
+			}
+			if !f.found {
+				*f = failure{value: v, found: true}
+			}
+			return nil, false
+		},
+	}
+}
+
+// Some matches zero or more of item with sep between them, its value is a []interface{} of the items
+func Some(item Syntax, sep Syntax) Syntax {
+	return Syntax{
+		parser: goparsify.Some(item.parser, sep.parser).Map(collect),
+		print: func(v interface{}, f *failure) (Doc, bool) {
+			values, ok := v.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			sepDoc, ok := sep.print(nil, f)
+			if !ok {
+				return nil, false
+			}
+
+			docs := make([]Doc, len(values))
+			for i, value := range values {
+				if docs[i], ok = item.print(value, f); !ok {
+					return nil, false
+				}
+			}
+			return Join(sepDoc, docs), true
+		},
+	}
+}
+
+// Map converts values in both directions, parse is given the parsed value and print turns it back, returning
+// false for values it can't convert
+func Map(syntax Syntax, parse func(v interface{}) interface{}, print func(v interface{}) (interface{}, bool)) Syntax {
+	return Syntax{
+		parser: syntax.parser.Map(func(n *goparsify.Result) { n.Result = parse(n.Result) }),
+		print: func(v interface{}, f *failure) (Doc, bool) {
+			converted, ok := print(v)
+			if !ok {
+				return nil, false
+			}
+			return syntax.print(converted, f)
+		},
+	}
+}
+
+// Group prints the syntax on one line if it fits, see the Group Doc
+func (s Syntax) Group() Syntax {
+	return Syntax{
+		parser: s.parser,
+		print: func(v interface{}, f *failure) (Doc, bool) {
+			doc, ok := s.print(v, f)
+			return Group(doc), ok
+		},
+	}
+}
+
+// Nest indents the lines in the syntax, see the Nest Doc
+func (s Syntax) Nest() Syntax {
+	return Syntax{
+		parser: s.parser,
+		print: func(v interface{}, f *failure) (Doc, bool) {
+			doc, ok := s.print(v, f)
+			return Nest(doc), ok
+		},
+	}
+}
+
+// Ref refers to a syntax that has not been built yet, for recursive grammars
+func Ref(syntax *Syntax) Syntax {
+	return Syntax{
+		parser: goparsify.Parsify(&syntax.parser),
+		print:  func(v interface{}, f *failure) (Doc, bool) { return syntax.print(v, f) },
+	}
+}
+
+func collect(n *goparsify.Result) {
+	values := make([]interface{}, len(n.Child))
+	for i, child := range n.Child {
+		values[i] = child.Result
+	}
+	n.Result = values
+}
@@ -0,0 +1,83 @@
+package pretty
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/goparsify"
+)
+
+func TestSyntax(t *testing.T) {
+	var value Syntax
+	items := Seq(SoftBreak, Some(Ref(&value), Seq(Exact(","), LineBreak))).Nest()
+	list := Map(
+		Seq(Exact("["), items, SoftBreak, Exact("]")).Group(),
+		func(v interface{}) interface{} { return v.([]interface{})[1].([]interface{})[1] },
+		func(v interface{}) (interface{}, bool) {
+			values, ok := v.([]interface{})
+			return []interface{}{nil, []interface{}{nil, values}, nil, nil}, ok
+		},
+	)
+	value = Any(Bind(Exact("nil"), nil), StringLit(), NumberLit(), list)
+
+	t.Run("parses", func(t *testing.T) {
+		result, err := value.Parse(`[1, "a", [nil, 2.5]]`, goparsify.ASCIIWhitespace)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{int64(1), "a", []interface{}{nil, 2.5}}, result)
+	})
+
+	t.Run("prints", func(t *testing.T) {
+		result, err := value.Format([]interface{}{1, "a", []interface{}{nil, 2.0}}, 80, "  ")
+		require.NoError(t, err)
+		require.Equal(t, `[1, "a", [nil, 2.0]]`, result)
+
+		result, err = value.Format([]interface{}{1, "a", []interface{}{nil, 2.0}}, 12, "  ")
+		require.NoError(t, err)
+		require.Equal(t, "[\n  1,\n  \"a\",\n  [nil, 2.0]\n]", result)
+	})
+
+	t.Run("round trips", func(t *testing.T) {
+		input := []interface{}{"q\"uo\\te\n", int64(-3), 1e100, []interface{}{}}
+		text, err := value.Format(input, 80, "  ")
+		require.NoError(t, err)
+		require.Equal(t, `["q\"uo\\te\u000a", -3, 1e+100, []]`, text)
+
+		result, err := value.Parse(text, goparsify.ASCIIWhitespace)
+		require.NoError(t, err)
+		require.Equal(t, input, result)
+	})
+
+	t.Run("cant print", func(t *testing.T) {
+		_, err := value.Format(true, 80, "  ")
+		require.EqualError(t, err, "cant print a `bool`")
+
+		_, err = value.Format([]interface{}{math.NaN()}, 80, "  ")
+		require.Error(t, err)
+	})
+
+	t.Run("binds values that cant be compared", func(t *testing.T) {
+		empty := Any(Bind(Exact("{}"), map[string]interface{}{}), value)
+
+		result, err := empty.Format(map[string]interface{}{}, 80, "  ")
+		require.NoError(t, err)
+		require.Equal(t, "{}", result)
+
+		result, err = empty.Format([]interface{}{1}, 80, "  ")
+		require.NoError(t, err)
+		require.Equal(t, "[1]", result)
+	})
+
+	t.Run("recursion counts towards MaxDepth", func(t *testing.T) {
+		ps := goparsify.NewState("[[[[[[1]]]]]]")
+		ps.MaxDepth = 3
+		_, err := goparsify.RunState(value.Parser(), ps)
+		require.IsType(t, &goparsify.DepthLimitError{}, err)
+	})
+
+	t.Run("usable as a parser", func(t *testing.T) {
+		result, err := goparsify.Run(goparsify.Seq("(", value.Parser(), ")"), "(1)")
+		require.NoError(t, err)
+		require.Nil(t, result)
+	})
+}
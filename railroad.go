@@ -0,0 +1,218 @@
+package goparsify
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"unicode/utf8"
+)
+
+// sizes used to lay out railroad diagrams, in pixels
+const (
+	arc       = 10
+	gap       = 10
+	charWidth = 8
+	boxHeight = 22
+)
+
+// track is a piece of railroad diagram. It is drawn with its entry on the left and exit on the right at the same
+// height, up and down are how far it reaches above and below that line.
+type track struct {
+	width, up, down int
+	draw            func(buf *bytes.Buffer, x, y int)
+}
+
+func line(buf *bytes.Buffer, x1, y1, x2, y2 int) {
+	if x1 != x2 || y1 != y2 {
+		fmt.Fprintf(buf, "<path d=\"M%d %dL%d %d\"/>\n", x1, y1, x2, y2)
+	}
+}
+
+func skipTrack() track {
+	return track{draw: func(buf *bytes.Buffer, x, y int) {}}
+}
+
+// boxTrack is a terminal, drawn with rounded corners, or a reference to a rule
+func boxTrack(text string, rounded bool) track {
+	width := utf8.RuneCountInString(text)*charWidth + 2*gap
+	radius := 0
+	if rounded {
+		radius = boxHeight / 2
+	}
+
+	return track{width: width, up: boxHeight / 2, down: boxHeight / 2, draw: func(buf *bytes.Buffer, x, y int) {
+		fmt.Fprintf(buf, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" rx=\"%d\"/>\n", x, y-boxHeight/2, width, boxHeight, radius)
+		fmt.Fprintf(buf, "<text x=\"%d\" y=\"%d\">%s</text>\n", x+width/2, y+4, html.EscapeString(text))
+	}}
+}
+
+func seqTrack(items []track) track {
+	if len(items) == 0 {
+		return skipTrack()
+	}
+	t := track{width: gap * (len(items) - 1)}
+	for _, item := range items {
+		t.width += item.width
+		t.up = maxOf(t.up, item.up)
+		t.down = maxOf(t.down, item.down)
+	}
+	t.draw = func(buf *bytes.Buffer, x, y int) {
+		for i, item := range items {
+			if i > 0 {
+				line(buf, x, y, x+gap, y)
+				x += gap
+			}
+			item.draw(buf, x, y)
+			x += item.width
+		}
+	}
+	return t
+}
+
+// choiceTrack puts the first item on the line and the rest below it
+func choiceTrack(items []track) track {
+	inner := 0
+	for _, item := range items {
+		inner = maxOf(inner, item.width)
+	}
+	offsets := make([]int, len(items))
+	for i := 1; i < len(items); i++ {
+		offsets[i] = offsets[i-1] + maxOf(items[i-1].down+gap+items[i].up, 2*arc)
+	}
+	last := len(items) - 1
+
+	t := track{width: inner + 4*arc, up: items[0].up, down: offsets[last] + items[last].down}
+	t.draw = func(buf *bytes.Buffer, x, y int) {
+		right := x + t.width
+		for i, item := range items {
+			iy := y + offsets[i]
+			if i == 0 {
+				line(buf, x, y, x+2*arc, y)
+			} else {
+				fmt.Fprintf(buf, "<path d=\"M%d %da%d %d 0 0 1 %d %dV%da%d %d 0 0 0 %d %d\"/>\n",
+					x, y, arc, arc, arc, arc, iy-arc, arc, arc, arc, arc)
+				fmt.Fprintf(buf, "<path d=\"M%d %da%d %d 0 0 0 %d %dV%da%d %d 0 0 1 %d %d\"/>\n",
+					right-2*arc, iy, arc, arc, arc, -arc, y+arc, arc, arc, arc, -arc)
+			}
+			item.draw(buf, x+2*arc, iy)
+			line(buf, x+2*arc+item.width, iy, right-2*arc, iy)
+			if i == 0 {
+				line(buf, right-2*arc, y, right, y)
+			}
+		}
+	}
+	return t
+}
+
+// loopTrack is one or more of item, going back around through sep below it
+func loopTrack(item track, sep track) track {
+	inner := maxOf(item.width, sep.width)
+	back := maxOf(item.down+gap+sep.up, 2*arc)
+
+	t := track{width: inner + 4*arc, up: item.up, down: back + sep.down}
+	t.draw = func(buf *bytes.Buffer, x, y int) {
+		right := x + t.width
+		line(buf, x, y, x+2*arc, y)
+		item.draw(buf, x+2*arc, y)
+		line(buf, x+2*arc+item.width, y, right, y)
+
+		by := y + back
+		fmt.Fprintf(buf, "<path d=\"M%d %da%d %d 0 0 1 %d %dV%da%d %d 0 0 1 %d %d\"/>\n",
+			right-2*arc, y, arc, arc, arc, arc, by-arc, arc, arc, -arc, arc)
+		line(buf, right-2*arc, by, x+2*arc+sep.width, by)
+		sep.draw(buf, x+2*arc, by)
+		fmt.Fprintf(buf, "<path d=\"M%d %da%d %d 0 0 1 %d %dV%da%d %d 0 0 1 %d %d\"/>\n",
+			x+2*arc, by, arc, arc, -arc, -arc, y+arc, arc, arc, arc, -arc)
+	}
+	return t
+}
+
+func maxOf(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// SVG draws a railroad diagram for each rule in the grammar, one under the other. Literals are drawn in rounded
+// boxes and references to other rules in square ones.
+func (g *Grammar) SVG() string {
+	body := &bytes.Buffer{}
+	width, y := 0, 0
+	for _, r := range g.rules {
+		t := g.track(r.body(), true)
+		y += 20
+		fmt.Fprintf(body, "<text class=\"rule\" x=\"10\" y=\"%d\">%s</text>\n", y, html.EscapeString(r.name))
+		y += 10 + t.up
+
+		// the start and end of each rule are marked with a short bar
+		line(body, 10, y-arc, 10, y+arc)
+		line(body, 10, y, 20, y)
+		t.draw(body, 20, y)
+		end := 20 + t.width
+		line(body, end, y, end+10, y)
+		line(body, end+10, y-arc, end+10, y+arc)
+
+		width = maxOf(width, end+20)
+		y += t.down + 10
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, y, width, y)
+	buf.WriteString("<style>path,rect{fill:none;stroke:black;stroke-width:1.5}rect{fill:#ffc}" +
+		"text{font:14px monospace;text-anchor:middle}text.rule{font-weight:bold;text-anchor:start}</style>\n")
+	buf.Write(body.Bytes())
+	buf.WriteString("</svg>\n")
+	return buf.String()
+}
+
+// track lays out a description, anything too complicated to draw is written as EBNF in a box
+func (g *Grammar) track(d *description, top bool) track {
+	if d.kind == refKind {
+		d = resolve(d)
+	}
+	if name, ok := g.names[d]; ok && !top {
+		return boxTrack(name, false)
+	}
+
+	switch d.kind {
+	case exactKind, charsKind, regexKind:
+		buf := &bytes.Buffer{}
+		g.expand(buf, d, postfixPrec)
+		return boxTrack(buf.String(), true)
+	case seqKind:
+		var items []track
+		for _, child := range d.children {
+			if child.kind != emptyKind {
+				items = append(items, g.track(child, false))
+			}
+		}
+		return seqTrack(items)
+	case anyKind:
+		items := make([]track, len(d.children))
+		for i, child := range d.children {
+			items[i] = g.track(child, false)
+		}
+		return choiceTrack(items)
+	case someKind, manyKind:
+		sep := skipTrack()
+		if len(d.children) > 1 {
+			sep = g.track(d.children[1], false)
+		}
+		loop := loopTrack(g.track(d.children[0], false), sep)
+		if d.kind == someKind {
+			return choiceTrack([]track{skipTrack(), loop})
+		}
+		return loop
+	case maybeKind:
+		return choiceTrack([]track{skipTrack(), g.track(d.children[0], false)})
+	case leftRecKind:
+		return g.track(d.children[0], false)
+	case emptyKind:
+		return skipTrack()
+	}
+
+	buf := &bytes.Buffer{}
+	g.expand(buf, d, postfixPrec)
+	return boxTrack(buf.String(), false)
+}
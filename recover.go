@@ -0,0 +1,52 @@
+package goparsify
+
+import "unicode/utf8"
+
+// Recover lets the parse carry on after parser fails, so more than one problem can be reported at once. When parser
+// fails after passing a Cut the error is added to State.Recovered, and the input from the error up to and including
+// the next match of sync is skipped, eg:
+//  statement := Recover(Seq(keyword, Cut(), args, ";"), ";")
+// The skipped input is matched as a placeholder whose .Result is the *Error. Failures before a cut are left alone as
+// they may only mean another alternative should be tried, and if sync never matches the error is not recovered.
+func Recover(parser Parserish, sync Parserish) Parser {
+	p := Parsify(parser)
+	s := Parsify(sync)
+
+	return describeAs(NewParser("Recover()", func(ps *State, node *Result) {
+		startpos, furthest := ps.Offset+ps.Pos, ps.furthest
+		p(ps, node)
+		if !ps.Errored() || ps.Cut <= startpos || ps.aborted() != nil {
+			return
+		}
+
+		err := ps.furthestError(furthest)
+		err.alternatives = append([]string(nil), err.alternatives...)
+		ps.Recover()
+
+		ps.rewind(err.pos)
+		for {
+			s(ps, TrashResult)
+			if !ps.Errored() {
+				break
+			}
+			ps.Recover()
+			if !ps.has(ps.Pos) {
+				ps.Error = err
+				ps.rewind(startpos)
+				return
+			}
+			if !utf8.FullRuneInString(ps.Get()) {
+				ps.fill(ps.Pos + utf8.UTFMax)
+			}
+			_, w := utf8.DecodeRuneInString(ps.Get())
+			ps.Advance(w)
+		}
+
+		// the failed attempts at sync are not what the input was expected to be
+		ps.furthest = err.pos
+		ps.expecting = append(ps.expecting[:0], err.Expected()...)
+
+		ps.Recovered = append(ps.Recovered, err)
+		*node = Result{Result: &err, Start: startpos, End: ps.Offset + ps.Pos}
+	}), p)
+}
@@ -0,0 +1,90 @@
+package goparsify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecover(t *testing.T) {
+	statement := Recover(Seq("set", Cut(), Chars("a-z"), "=", NumberLit(), ";"), ";")
+	statements := Some(Any(statement, Seq("#", Until("\n"))))
+
+	t.Run("matches", func(t *testing.T) {
+		result, ps := runParser("set a = 1; set b = 2;", statements)
+		require.False(t, ps.Errored())
+		require.Empty(t, ps.Recovered)
+		require.Len(t, result.Child, 2)
+	})
+
+	t.Run("records errors and carries on", func(t *testing.T) {
+		result, ps := runParser("set a 1; set b = 2; set = 3; set c = 4;", statements)
+		require.False(t, ps.Errored())
+		require.Equal(t, "", ps.Get())
+		require.Len(t, result.Child, 4)
+
+		require.Len(t, ps.Recovered, 2)
+		require.Equal(t, 6, ps.Recovered[0].Pos())
+		require.Equal(t, []string{"'='"}, ps.Recovered[0].Expected())
+		require.Equal(t, 24, ps.Recovered[1].Pos())
+
+		placeholder := result.Child[0]
+		require.Equal(t, &ps.Recovered[0], placeholder.Result)
+		require.Equal(t, 0, placeholder.Start)
+		require.Equal(t, 8, placeholder.End)
+		require.Equal(t, int64(2), result.Child[1].Child[4].Result)
+	})
+
+	t.Run("leaves errors before a cut to backtracking", func(t *testing.T) {
+		result, ps := runParser("# set a 1\nset b = 2;", statements)
+		require.False(t, ps.Errored())
+		require.Empty(t, ps.Recovered)
+		require.Len(t, result.Child, 2)
+	})
+
+	t.Run("does not recover without a sync point", func(t *testing.T) {
+		_, ps := runParser("set a 1", statements)
+		require.Empty(t, ps.Recovered)
+		require.Equal(t, 0, ps.Pos)
+	})
+
+	t.Run("forgets errors from attempts that are backtracked over", func(t *testing.T) {
+		_, ps := runParser("set a 1;", Seq(Not(statement), Until(";")))
+		require.True(t, ps.Errored())
+		require.Empty(t, ps.Recovered)
+
+		_, ps = runParser("set a 1;", Seq(Peek(statement), "set", Until(";"), ";"))
+		require.False(t, ps.Errored())
+		require.Empty(t, ps.Recovered)
+	})
+
+	t.Run("memo replays recovered errors", func(t *testing.T) {
+		memo := Memo(statement)
+		_, ps := runParser("set a 1;", Seq(Peek(memo), memo))
+		require.False(t, ps.Errored())
+		require.Len(t, ps.Recovered, 1)
+		require.Equal(t, 6, ps.Recovered[0].Pos())
+	})
+
+	t.Run("left recursion only keeps the errors from the longest match", func(t *testing.T) {
+		var list Parser
+		list = LeftRec(Any(Seq(&list, ",", statement), statement))
+		_, ps := runParser("set a 1;, set b = 2;, set = 3;", list)
+		require.False(t, ps.Errored())
+		require.Len(t, ps.Recovered, 2)
+		require.Equal(t, 6, ps.Recovered[0].Pos())
+		require.Equal(t, 26, ps.Recovered[1].Pos())
+	})
+
+	t.Run("run returns every error", func(t *testing.T) {
+		_, err := Run(statements, "set a 1;\nset b = 2;\nset = 3;\n!")
+		require.EqualError(t, err, "1:7: expected '='\nset a 1;\n      ^\n"+
+			"3:5: expected a-z\nset = 3;\n    ^\n"+
+			"4:1: left unparsed\n!\n^")
+
+		list := err.(ErrorList)
+		require.Len(t, list, 3)
+		require.Equal(t, 1, list[0].(*Error).Line())
+		require.IsType(t, UnparsedInputError{}, list[2])
+	})
+}
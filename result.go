@@ -15,6 +15,34 @@ type Result struct {
 	Token  string
 	Child  []Result
 	Result interface{}
+	// Start and End are the byte offsets into the input that were matched, see LineCol. When reading
+	// from a stream they are counted from the start of the stream.
+	Start int
+	End   int
+}
+
+// LineCol converts a byte offset into input, like Result.Start, into a 1 based line and column.
+// Columns are counted in runes.
+func LineCol(input string, offset int) (line int, col int) {
+	return lineOf(input, nil, offset), columnOf(input, nil, offset)
+}
+
+// spanChildren sets the span to cover all of the children that matched something, or
+// an empty span at pos if none did.
+func (r *Result) spanChildren(pos int) {
+	r.Start, r.End = pos, pos
+	for i := range r.Child {
+		if r.Child[i].End > r.Child[i].Start {
+			r.Start = r.Child[i].Start
+			break
+		}
+	}
+	for i := len(r.Child) - 1; i >= 0; i-- {
+		if r.Child[i].End > r.Child[i].Start {
+			r.End = r.Child[i].End
+			break
+		}
+	}
 }
 
 // String stringifies a node. This is only called from debug code.
@@ -13,3 +13,93 @@ func TestResult_String(t *testing.T) {
 	require.Equal(t, "10", Result{Result: 10}.String())
 	require.Equal(t, "10", Result{Result: big.NewInt(10)}.String())
 }
+
+func TestResult_Spans(t *testing.T) {
+	t.Run("tokens", func(t *testing.T) {
+		tests := map[string]Parser{
+			"exact":  Exact("foo"),
+			"char":   Exact("f"),
+			"chars":  Chars("a-z"),
+			"regex":  Regex("[a-z]+"),
+			"string": StringLit(`"`),
+			"number": NumberLit(),
+			"until":  Until(";"),
+		}
+		inputs := map[string]string{
+			"exact":  "  foo;",
+			"char":   "  f;",
+			"chars":  "  foo;",
+			"regex":  "  foo;",
+			"string": `  "f\"o";`,
+			"number": "  -1.5;",
+			"until":  "foo;",
+		}
+		for name, parser := range tests {
+			t.Run(name, func(t *testing.T) {
+				input := inputs[name]
+				node, ps := runParser(input, parser)
+				require.False(t, ps.Errored())
+				require.Equal(t, input[:len(input)-1], input[:node.End])
+				require.Equal(t, ps.Pos, node.End)
+				if name == "until" {
+					require.Equal(t, 0, node.Start)
+				} else {
+					require.Equal(t, 2, node.Start)
+				}
+			})
+		}
+	})
+
+	t.Run("seq", func(t *testing.T) {
+		node, _ := runParser(" a b  c ", Seq("a", Cut(), "b", Maybe("x"), "c"))
+		require.Equal(t, 1, node.Start)
+		require.Equal(t, 7, node.End)
+		require.Equal(t, 3, node.Child[2].Start)
+		require.Equal(t, 4, node.Child[2].End)
+	})
+
+	t.Run("any", func(t *testing.T) {
+		node, _ := runParser(" b", Any("a", Map("b", func(n *Result) { n.Result = n.Token })))
+		require.Equal(t, 1, node.Start)
+		require.Equal(t, 2, node.End)
+	})
+
+	t.Run("some", func(t *testing.T) {
+		node, _ := runParser("a, a ,a ;", Some("a", ","))
+		require.Equal(t, 0, node.Start)
+		require.Equal(t, 7, node.End)
+		require.Equal(t, 6, node.Child[2].Start)
+	})
+
+	t.Run("many", func(t *testing.T) {
+		node, _ := runParser(" aa a", Many("a"))
+		require.Equal(t, 1, node.Start)
+		require.Equal(t, 5, node.End)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		node, ps := runParser("b", Some("a"))
+		require.False(t, ps.Errored())
+		require.Equal(t, 0, node.Start)
+		require.Equal(t, 0, node.End)
+	})
+}
+
+func TestLineCol(t *testing.T) {
+	input := "one\ntwo 👺 three\n\nfour"
+
+	line, col := LineCol(input, 0)
+	require.Equal(t, []int{1, 1}, []int{line, col})
+
+	line, col = LineCol(input, 4)
+	require.Equal(t, []int{2, 1}, []int{line, col})
+
+	line, col = LineCol(input, 12)
+	require.Equal(t, []int{2, 6}, []int{line, col})
+
+	line, col = LineCol(input, 19)
+	require.Equal(t, []int{3, 1}, []int{line, col})
+
+	line, col = LineCol(input, len(input))
+	require.Equal(t, []int{4, 5}, []int{line, col})
+}
@@ -0,0 +1,177 @@
+package goparsify
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Scanner parses a stream of top level items one at a time, eg JSON lines, a log file or commands typed into a REPL:
+//  s := NewScanner(value, os.Stdin)
+//  for s.Next() {
+//      fmt.Println(s.Result())
+//  }
+//  if err := s.Err(); err != nil {
+//      ...
+//  }
+// Each item is committed to once it has been parsed, so the input behind it is discarded even if the item
+// parser never cuts.
+type Scanner struct {
+	parser Parser
+	ps     *State
+	result Result
+	item   int
+	err    error
+	skip   bool
+	done   bool
+}
+
+// ItemError is returned by Scanner.Err when one of the items could not be parsed
+type ItemError struct {
+	// Item is the 1 based number of the item that failed
+	Item int
+	// Err is the parse error, an *Error or an UnparsedInputError when the parser matched nothing. When a limit or
+	// the context set on the Scanner stopped the parse it is the error RunState would have returned.
+	Err error
+}
+
+// Error satisfies the golang error interface
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d: %s", e.Item, e.Err.Error())
+}
+
+// Unwrap returns the parse error
+func (e *ItemError) Unwrap() error { return e.Err }
+
+// NewScanner creates a Scanner that matches parser repeatedly against the input read from r, with optional
+// whitespace rules like Run.
+func NewScanner(parser Parserish, r io.Reader, ws ...VoidParser) *Scanner {
+	s := &Scanner{parser: Parsify(parser), ps: NewReaderState(r)}
+	if len(ws) > 0 {
+		s.ps.WS = ws[0]
+	}
+	return s
+}
+
+// SkipErrors makes the Scanner carry on past items that fail to parse, skipping to the start of the line after the
+// error. Next still returns true for the item that failed, with the error in Err and no Result.
+func (s *Scanner) SkipErrors() {
+	s.skip = true
+}
+
+// SetLimits limits how much work parsing each item can do, like State.MaxSteps, MaxDepth and MaxNodes. They are
+// counted from zero for every item, so they don't cut a long stream of small items short. Zero means no limit.
+// An item that goes over one stops the Scanner, even with SkipErrors.
+func (s *Scanner) SetLimits(maxSteps, maxDepth, maxNodes int) {
+	s.ps.MaxSteps, s.ps.MaxDepth, s.ps.MaxNodes = maxSteps, maxDepth, maxNodes
+}
+
+// SetContext stops the Scanner with ctx.Err() once ctx is done, like RunContext. The error is for the item that
+// was being parsed at the time.
+func (s *Scanner) SetContext(ctx context.Context) {
+	s.ps.Context = ctx
+}
+
+// Next parses the next item, returning false once the input runs out or an item fails. An item that was matched
+// before reading the input failed is still returned, the read error comes from Err once Next returns false.
+func (s *Scanner) Next() bool {
+	if s.done {
+		return false
+	}
+	s.result = Result{}
+	s.err = nil
+
+	ps := s.ps
+	ps.Recovered = ps.Recovered[:0]
+	ps.WS(ps)
+	if ps.Errored() {
+		s.item++
+		err := ps.Error
+		ps.Recover()
+		return s.fail(&err, err.pos)
+	}
+	if !ps.has(ps.Pos) {
+		return s.stop(nil)
+	}
+
+	s.item++
+	start := ps.Offset + ps.Pos
+	ps.Cut = start
+	ps.discard()
+	if ps.counters != nil {
+		ps.counters.steps, ps.counters.nodes, ps.counters.nextCheck = 0, 0, 0
+	}
+
+	s.parser(ps, &s.result)
+
+	if abort := ps.aborted(); abort != nil {
+		s.result = Result{}
+		return s.stop(&ItemError{Item: s.item, Err: abort})
+	}
+	if ps.Errored() {
+		// the item was probably cut short by the read error, the parse error would only be misleading
+		if readErr := ps.readError(); readErr != nil {
+			return s.stop(readErr)
+		}
+		err := ps.furthestError(start)
+		err.alternatives = append([]string(nil), err.alternatives...)
+		ps.Recover()
+		return s.fail(&err, err.pos)
+	}
+
+	// a parser that matches nothing would match nothing forever
+	if ps.Offset+ps.Pos == start {
+		return s.fail(UnparsedInputError{start, ps.Input, ps.discardedInput()}, start)
+	}
+
+	return true
+}
+
+// Result is the .Result of the item matched by the last call to Next
+func (s *Scanner) Result() interface{} {
+	return s.result.Result
+}
+
+// Recovered returns the errors that Recover skipped over while parsing the item matched by the last call to Next
+func (s *Scanner) Recovered() ErrorList {
+	var errs ErrorList
+	for i := range s.ps.Recovered {
+		err := s.ps.Recovered[i]
+		errs = append(errs, &err)
+	}
+	return errs
+}
+
+// Err returns the error that stopped the Scanner, or with SkipErrors the error for the last item. Parse errors
+// are wrapped in an *ItemError. Reaching the end of the input is not an error.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+func (s *Scanner) stop(err error) bool {
+	if err == nil {
+		err = s.ps.readError()
+	}
+	s.err = err
+	s.done = true
+	return false
+}
+
+func (s *Scanner) fail(err error, pos int) bool {
+	s.result = Result{}
+	s.err = &ItemError{Item: s.item, Err: err}
+	if !s.skip {
+		s.done = true
+		return false
+	}
+
+	ps := s.ps
+	ps.rewind(pos)
+	for ps.has(ps.Pos) {
+		ps.Pos++
+		if ps.Input[ps.Pos-1] == '\n' {
+			break
+		}
+	}
+	return true
+}
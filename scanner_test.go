@@ -0,0 +1,136 @@
+package goparsify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func scanAll(s *Scanner) []interface{} {
+	results := []interface{}{}
+	for s.Next() {
+		results = append(results, s.Result())
+	}
+	return results
+}
+
+func TestScanner(t *testing.T) {
+	item := Any(NumberLit(), StringLit(`"`).Map(func(n *Result) { n.Result = n.Token }), Bind("true", true))
+
+	t.Run("items", func(t *testing.T) {
+		s := NewScanner(item, strings.NewReader("1\n\"two\"\ntrue\n 4 5\n"))
+		require.Equal(t, []interface{}{int64(1), "two", true, int64(4), int64(5)}, scanAll(s))
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		s := NewScanner(item, strings.NewReader("  \n "))
+		require.Empty(t, scanAll(s))
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("errors report the item", func(t *testing.T) {
+		s := NewScanner(item, strings.NewReader("1\n2\nfalse\n4"))
+		require.Equal(t, []interface{}{int64(1), int64(2)}, scanAll(s))
+		require.EqualError(t, s.Err(), "item 3: 3:1: expected one of: number, \", 'true'\nfalse\n^")
+
+		var itemErr *ItemError
+		require.True(t, errors.As(s.Err(), &itemErr))
+		require.Equal(t, 3, itemErr.Item)
+		require.Equal(t, 3, itemErr.Err.(*Error).Line())
+		require.False(t, s.Next())
+	})
+
+	t.Run("skipping errors", func(t *testing.T) {
+		s := NewScanner(item, strings.NewReader("1\n2 false 3\n4"))
+		s.SkipErrors()
+
+		results := []interface{}{}
+		failed := []int{}
+		for s.Next() {
+			if err := s.Err(); err != nil {
+				failed = append(failed, err.(*ItemError).Item)
+				continue
+			}
+			results = append(results, s.Result())
+		}
+		require.Equal(t, []interface{}{int64(1), int64(2), int64(4)}, results)
+		require.Equal(t, []int{3}, failed)
+		require.NoError(t, s.Err())
+	})
+
+	t.Run("parsers that match nothing", func(t *testing.T) {
+		s := NewScanner(Maybe("a"), strings.NewReader("a\nb"))
+		require.Len(t, scanAll(s), 1)
+		require.EqualError(t, s.Err(), "item 2: 2:1: left unparsed\nb\n^")
+	})
+
+	t.Run("read errors", func(t *testing.T) {
+		broken := errors.New("broken pipe")
+		s := NewScanner(item, io.MultiReader(strings.NewReader("1 2"), iotest.ErrReader(broken)))
+		require.Equal(t, []interface{}{int64(1), int64(2)}, scanAll(s))
+		require.Equal(t, broken, s.Err())
+
+		s = NewScanner(item, io.MultiReader(strings.NewReader("1 2"), iotest.ErrReader(broken)))
+		require.True(t, s.Next())
+		require.True(t, s.Next())
+		require.NoError(t, s.Err())
+		require.Equal(t, int64(2), s.Result())
+		require.False(t, s.Next())
+		require.Equal(t, broken, s.Err())
+	})
+
+	t.Run("recovered errors", func(t *testing.T) {
+		stmt := Seq("let", Cut(), Chars("a-z"), ";")
+		s := NewScanner(Recover(stmt, ";"), strings.NewReader("let a;\nlet 1;\nlet b;"))
+
+		recovered := []int{}
+		for s.Next() {
+			recovered = append(recovered, len(s.Recovered()))
+		}
+		require.NoError(t, s.Err())
+		require.Equal(t, []int{0, 1, 0}, recovered)
+
+		s = NewScanner(Recover(stmt, ";"), strings.NewReader("let 1;"))
+		require.True(t, s.Next())
+		require.EqualError(t, s.Recovered()[0], "1:5: expected a-z\nlet 1;\n    ^")
+	})
+
+	t.Run("limits each item", func(t *testing.T) {
+		var list Parser
+		list = Seq("[", Some(&list, ","), "]")
+		input := strings.Repeat("[[],[]]\n", 100) + strings.Repeat("[", 20) + strings.Repeat("]", 20) + "\n[]"
+
+		s := NewScanner(list, strings.NewReader(input))
+		s.SetLimits(100, 10, 100)
+		s.SkipErrors()
+		require.Len(t, scanAll(s), 100)
+
+		var itemErr *ItemError
+		require.True(t, errors.As(s.Err(), &itemErr))
+		require.Equal(t, 101, itemErr.Item)
+		require.IsType(t, &DepthLimitError{}, itemErr.Err)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		s := NewScanner(Seq("a", "b"), strings.NewReader("ab ab"))
+		s.SetContext(ctx)
+		require.Empty(t, scanAll(s))
+		require.True(t, errors.Is(s.Err(), context.Canceled))
+	})
+
+	t.Run("discards finished items", func(t *testing.T) {
+		s := NewScanner(item, strings.NewReader(strings.Repeat("12345\n", 10000)))
+		require.Len(t, scanAll(s), 10000)
+		require.NoError(t, s.Err())
+		require.True(t, s.ps.Offset > 30000)
+	})
+}
@@ -1,6 +1,8 @@
 package goparsify
 
 import (
+	"context"
+	"fmt"
 	"strconv"
 	"unicode"
 	"unicode/utf8"
@@ -8,10 +10,13 @@ import (
 
 // State is the current parse state. It is entirely public because parsers are expected to mutate it during the parse.
 type State struct {
-	// The full input string
+	// The full input string, or the part of the stream that has not been discarded yet, see NewReaderState
 	Input string
 	// An offset into the string, pointing to the current tip
 	Pos int
+	// How many bytes of the stream have been discarded from the front of Input. Cut, error positions and
+	// Result spans are offsets into the whole document, so Input[p-Offset] is the byte at offset p.
+	Offset int
 	// Do not backtrack past this point
 	Cut int
 	// Error is a secondary return channel from parsers, but used so heavily
@@ -19,12 +24,45 @@ type State struct {
 	Error Error
 	// Called to determine what to ignore when WS is called, or when WS fires
 	WS VoidParser
+	// Errors that Recover has skipped over, in the order they were found
+	Recovered []Error
+	// Arena, when set, is where the .Child slices of Results come from, see Arena
+	Arena *Arena
+	// CopyTokens makes every Token and Trivia a copy instead of a part of Input, so they can outlive the []byte
+	// given to NewStateBytes. Errors are copied too.
+	CopyTokens bool
+
+	// Limits on how much work a parse can do before it gives up with a StepLimitError, DepthLimitError or
+	// NodeLimitError, for input that can't be trusted. Zero means no limit.
+	//  - MaxSteps is how many times Seq, Any, Some, Many and *Parser references can run, each Some or Many item and
+	//    each operator Expr applies counts
+	//  - MaxDepth is how many *Parser references and Expr operands can be running at once, recursive grammars go
+	//    through them
+	//  - MaxNodes is how many Results Seq, Some and Many can build, including the ones backtracked over
+	MaxSteps int
+	MaxDepth int
+	MaxNodes int
+	// Context stops the parse with its error once it is done, see RunContext
+	Context context.Context
+
+	// every expectation that failed at the furthest offset reached so far, used to
+	// explain all the alternatives that would have been valid when the parse fails.
+	furthest  int
+	expecting []string
+	// failures inside Not and Peek are not what the input was expected to be
+	peeking int
+
+	// the rest is only needed by some parses, so it is only allocated when it is used
+	packrat  *packrat
+	stream   *stream
+	borrowed *borrowedInput
+	counters *counters
 }
 
 // ASCIIWhitespace matches any of the standard whitespace characters. It is faster
 // than the UnicodeWhitespace parser as it does not need to decode unicode runes.
 func ASCIIWhitespace(s *State) {
-	for s.Pos < len(s.Input) {
+	for s.has(s.Pos) {
 		switch s.Input[s.Pos] {
 		case '\t', '\n', '\v', '\f', '\r', ' ':
 			s.Pos++
@@ -37,7 +75,10 @@ func ASCIIWhitespace(s *State) {
 // UnicodeWhitespace matches any unicode space character. Its a little slower
 // than the ascii parser because it matches a rune at a time.
 func UnicodeWhitespace(s *State) {
-	for s.Pos < len(s.Input) {
+	for s.has(s.Pos) {
+		if !utf8.FullRuneInString(s.Get()) {
+			s.fill(s.Pos + utf8.UTFMax)
+		}
 		r, w := utf8.DecodeRuneInString(s.Get())
 		if !unicode.IsSpace(r) {
 			return
@@ -89,13 +130,106 @@ func (s *State) Preview(x int) string {
 
 // ErrorHere raises an error at the current position.
 func (s *State) ErrorHere(expected string) {
-	s.Error.pos = s.Pos
+	s.ErrorAt(s.Pos, expected)
+}
+
+// ErrorAt raises an error at the given position in Input.
+func (s *State) ErrorAt(pos int, expected string) {
+	s.Error.pos = s.Offset + pos
 	s.Error.expected = expected
+	s.Error.input = s.Input
+	s.Error.discarded = s.discardedInput()
+	s.expect(s.Error.pos, expected)
+}
+
+// rewind moves Pos back to a document offset saved by a combinator. A stream may have discarded it already, which
+// is only fine when failing past the cut that let it be discarded, as nothing can backtrack there again. Anything
+// else is a bug in a parser, and it panics rather than carrying on from the wrong place.
+func (s *State) rewind(offset int) {
+	s.Pos = offset - s.Offset
+	if s.Pos < 0 {
+		if !s.Errored() || s.Cut <= offset {
+			panic(fmt.Sprintf("goparsify: can't go back to offset %d, the stream has been discarded up to %d", offset, s.Offset))
+		}
+		s.Pos = 0
+	}
+}
+
+// forget drops the errors recovered from since there were n of them, when the attempt that found them is
+// backtracked over
+func (s *State) forget(n int) {
+	s.Recovered = s.Recovered[:n]
+}
+
+// reclaim gives everything a failed parser took from the Arena since mark back, along with node's children
+func (s *State) reclaim(mark arenaMark, node *Result) {
+	if s.Arena != nil {
+		s.Arena.reset(mark)
+		node.Child = nil
+	}
+}
+
+// lookahead runs a parser and puts Pos and Cut back how they were, without recording what it expected.
+func (s *State) lookahead(p Parser, node *Result) (startpos int) {
+	startpos, cut, recovered := s.Offset+s.Pos, s.Cut, len(s.Recovered)
+	hold := s.holdFrom(startpos)
+
+	s.peeking++
+	p(s, node)
+	s.peeking--
+
+	s.release(hold)
+	s.Cut = cut
+	s.rewind(startpos)
+	s.forget(recovered)
+	return startpos
+}
+
+// expect records a failed expectation if it is at least as far into the input as any seen before.
+func (s *State) expect(pos int, expected string) {
+	if pos < s.furthest || s.peeking > 0 {
+		return
+	}
+	if pos > s.furthest || len(s.expecting) == 0 {
+		s.furthest = pos
+		if s.expecting == nil {
+			s.expecting = make([]string, 0, 4)
+		}
+		s.expecting = append(s.expecting[:0], expected)
+		return
+	}
+	for _, e := range s.expecting {
+		if e == expected {
+			return
+		}
+	}
+	s.expecting = append(s.expecting, expected)
+}
+
+// furthestError is the current error, moved to the furthest any parser got past since if that is further.
+// Backtracking usually leaves an error from where the last alternative started, which is rarely where the
+// problem is.
+func (s *State) furthestError(since int) Error {
+	err := s.Error
+	if len(s.expecting) > 0 && s.furthest > err.pos && s.furthest > since {
+		err.pos = s.furthest
+		err.expected = s.expecting[0]
+		err.input = s.Input
+		err.discarded = s.discardedInput()
+	}
+	err.input = s.detach(err.input)
+	if err.pos == s.furthest {
+		err.alternatives = s.expecting
+	}
+	return err
 }
 
 // Recover from the current error. Often called by combinators that can match
 // when one of their children succeed, but others have failed.
 func (s *State) Recover() {
+	if s.aborted() != nil {
+		return
+	}
 	s.Error.expected = ""
 }
 
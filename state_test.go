@@ -30,7 +30,7 @@ func TestState_Errors(t *testing.T) {
 	ps := NewState("fooo")
 
 	ps.ErrorHere("hello")
-	require.Equal(t, "offset 0: expected hello", ps.Error.Error())
+	require.Equal(t, "hello", ps.Error.expected)
 	require.Equal(t, 0, ps.Error.Pos())
 	require.True(t, ps.Errored())
 
@@ -39,7 +39,7 @@ func TestState_Errors(t *testing.T) {
 
 	ps.Advance(2)
 	ps.ErrorHere("hello2")
-	require.Equal(t, "offset 2: expected hello2", ps.Error.Error())
+	require.Equal(t, "hello2", ps.Error.expected)
 	require.Equal(t, 2, ps.Error.Pos())
 	require.True(t, ps.Errored())
 }
@@ -54,7 +54,7 @@ func TestWhitespaces(t *testing.T) {
 	p := Many(Any("hello", "world", "!"))
 
 	_, err := Run(p, "hello world\u2005!", ASCIIWhitespace)
-	require.Equal(t, "left unparsed: \u2005!", err.Error())
+	require.Equal(t, "\u2005!", err.(UnparsedInputError).Remaining())
 
 	_, err = Run(p, "hello world\u2005!", UnicodeWhitespace)
 	require.NoError(t, err)
@@ -0,0 +1,166 @@
+package goparsify
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxInt = int(^uint(0) >> 1)
+
+// readSize is the smallest read from a stream, and the least input worth discarding at once
+const readSize = 4096
+
+// stream is the part of a State that reads its input from an io.Reader
+type stream struct {
+	reader    io.Reader
+	err       error
+	chunk     []byte
+	discarded *discarded
+	// input from this offset on must be kept even when it is behind a cut
+	hold int
+}
+
+// NewReaderState creates a new State that reads its input from r as the parsers need it. Input behind the
+// latest Cut can never be backtracked into, so it is discarded as the parse goes and a grammar that cuts
+// regularly can parse streams much larger than memory.
+func NewReaderState(r io.Reader) *State {
+	s := NewState("")
+	s.stream = &stream{reader: r, hold: maxInt}
+	return s
+}
+
+// RunReader is Run for input read from a stream, see NewReaderState. Read errors other than io.EOF
+// are returned in preference to parse errors.
+func RunReader(parser Parserish, r io.Reader, ws ...VoidParser) (result interface{}, err error) {
+	return run(Parsify(parser), NewReaderState(r), ws)
+}
+
+// has reports whether Input extends to index i, reading more of the stream if it needs to.
+func (s *State) has(i int) bool {
+	return i < len(s.Input) || s.fill(i+1)
+}
+
+// fill reads from the stream until Input is at least n bytes long, or the stream runs out.
+// It only ever appends to Input, so indexes into it stay valid.
+func (s *State) fill(n int) bool {
+	for len(s.Input) < n {
+		if s.stream == nil || s.stream.err != nil {
+			return false
+		}
+		st := s.stream
+
+		// read at least as much as is buffered so copying the buffer on each read stays linear
+		size := readSize
+		if len(s.Input) > size {
+			size = len(s.Input)
+		}
+		if cap(st.chunk) < size {
+			st.chunk = make([]byte, size)
+		}
+
+		read, err := st.reader.Read(st.chunk[:size])
+		s.Input += string(st.chunk[:read])
+		if err != nil {
+			st.err = err
+		}
+	}
+	return true
+}
+
+// discard drops the input behind the cut from the front of Input, if there is enough of it to bother.
+func (s *State) discard() {
+	st := s.stream
+	if st == nil {
+		return
+	}
+	keep := s.Cut
+	if st.hold < keep {
+		keep = st.hold
+	}
+	n := keep - s.Offset
+	if n < readSize || n > s.Pos {
+		return
+	}
+
+	gone := s.Input[:n]
+	d := &discarded{offset: s.Offset + n}
+	if st.discarded != nil {
+		*d = *st.discarded
+		d.offset = s.Offset + n
+	}
+	if nl := strings.LastIndexByte(gone, '\n'); nl != -1 {
+		d.lines += strings.Count(gone, "\n")
+		d.lineStart = s.Offset + nl + 1
+		d.lineRunes = utf8.RuneCountInString(gone[nl+1:])
+	} else {
+		d.lineRunes += utf8.RuneCountInString(gone)
+	}
+
+	st.discarded = d
+	s.Input = s.Input[n:]
+	s.Offset += n
+	s.Pos -= n
+
+	if s.packrat != nil {
+		for key := range s.packrat.memo {
+			if key.pos < s.Offset {
+				delete(s.packrat.memo, key)
+			}
+		}
+	}
+}
+
+// holdFrom stops input from offset on being discarded, until release is called with what it returns
+func (s *State) holdFrom(offset int) (hold int) {
+	if s.stream == nil {
+		return 0
+	}
+	hold = s.stream.hold
+	if offset < hold {
+		s.stream.hold = offset
+	}
+	return hold
+}
+
+func (s *State) release(hold int) {
+	if s.stream != nil {
+		s.stream.hold = hold
+	}
+}
+
+// discardedInput is what has been dropped from the front of Input, nil unless reading from a stream
+func (s *State) discardedInput() *discarded {
+	if s.stream == nil {
+		return nil
+	}
+	return s.stream.discarded
+}
+
+// readError is the error reading the stream stopped with, unless it is io.EOF
+func (s *State) readError() error {
+	if s.stream == nil || s.stream.err == io.EOF {
+		return nil
+	}
+	return s.stream.err
+}
+
+// runeReader lets the regexp package read as far into a stream as it needs to
+type runeReader struct {
+	ps  *State
+	pos int
+}
+
+func (r *runeReader) ReadRune() (rune, int, error) {
+	if !r.ps.has(r.pos) {
+		return 0, 0, io.EOF
+	}
+	if c := r.ps.Input[r.pos]; c < utf8.RuneSelf {
+		r.pos++
+		return rune(c), 1, nil
+	}
+	r.ps.fill(r.pos + utf8.UTFMax)
+	c, w := utf8.DecodeRuneInString(r.ps.Input[r.pos:])
+	r.pos += w
+	return c, w, nil
+}
@@ -0,0 +1,119 @@
+package goparsify
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunReader(t *testing.T) {
+	// reading a byte at a time puts a buffer boundary everywhere a parser could trip over one
+	tests := map[string]struct {
+		parser Parser
+		input  string
+	}{
+		"exact":       {Seq("hello", "world"), "hello world"},
+		"chars":       {Chars("a-zé"), "héllo"},
+		"until":       {Seq(Until("-->"), "-->"), "hello - -> --->"},
+		"string":      {StringLit(`"`), `"h\"ellé wörld"`},
+		"number":      {NumberLit(), "-1.23e+4"},
+		"regex":       {Regex("[a-z]+[0-9]*"), "hello123"},
+		"whitespace":  {Seq("a", "b"), "a  \t b"},
+		"many":        {Some(Any(NumberLit(), StringLit(`"`)), ","), `1, "two", 3`},
+		"spans":       {Seq("a", Chars("b")).Map(func(n *Result) { n.Result = [2]int{n.Start, n.End} }), " ab"},
+		"unicode eof": {Chars("é"), "éé"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			expected, err := Run(test.parser, test.input)
+			require.NoError(t, err)
+
+			result, err := RunReader(test.parser, iotest.OneByteReader(strings.NewReader(test.input)))
+			require.NoError(t, err)
+			require.Equal(t, expected, result)
+		})
+	}
+
+	t.Run("unparsed input", func(t *testing.T) {
+		_, err := RunReader("hello", strings.NewReader("hello world"))
+		require.EqualError(t, err, "1:7: left unparsed\nhello world\n      ^")
+	})
+
+	t.Run("read errors", func(t *testing.T) {
+		broken := errors.New("broken pipe")
+		_, err := RunReader(Some("a"), io.MultiReader(strings.NewReader("aaa"), iotest.ErrReader(broken)))
+		require.Equal(t, broken, err)
+	})
+}
+
+func TestReaderState_Discard(t *testing.T) {
+	item := Seq("{", Cut(), StringLit(`"`), "}")
+	input := strings.Repeat(`{"item"}`+"\n", 10000)
+
+	t.Run("behind cuts", func(t *testing.T) {
+		ps := NewReaderState(strings.NewReader(input))
+		result := Result{}
+		Some(item)(ps, &result)
+
+		require.False(t, ps.Errored())
+		require.Len(t, result.Child, 10000)
+		require.Equal(t, len(input)-1, ps.Offset+ps.Pos)
+		require.True(t, ps.Offset > len(input)/2)
+		require.True(t, len(ps.Input) < len(input)/2)
+		require.Equal(t, len(input)-9, result.Child[9999].Start)
+	})
+
+	t.Run("errors still know their line", func(t *testing.T) {
+		_, err := RunReader(Some(item), strings.NewReader(input+`{"item" }`+"\n"+`{"item"`))
+		require.EqualError(t, err, "10002:8: expected '}'\n{\"item\"\n       ^")
+		require.Equal(t, 10002, err.(*Error).Line())
+		require.Equal(t, len(input)+17, err.(*Error).Pos())
+	})
+
+	t.Run("part of a line", func(t *testing.T) {
+		long := strings.Repeat(`{"item"}`, 1000)
+		_, err := RunReader(Some(item), strings.NewReader("\n"+long+"x"))
+		require.Error(t, err)
+		require.Equal(t, 2, err.(UnparsedInputError).Line())
+		require.Equal(t, len(long)+1, err.(UnparsedInputError).Column())
+		require.Equal(t, len(long)+1, err.(UnparsedInputError).ByteColumn())
+	})
+
+	t.Run("errors after a cut on the boundary", func(t *testing.T) {
+		// 1365 items end 1 byte short of readSize, so the failing item's cut discards exactly up to it
+		for _, n := range []int{1364, 1365, 1366} {
+			braces := Some(Seq("{", Cut(), "x", "}"))
+			input := strings.Repeat("{x}", n) + "{y}"
+
+			_, err := RunReader(braces, strings.NewReader(input))
+			require.Error(t, err, n)
+			require.Equal(t, 1, err.(*Error).Line(), n)
+			require.Equal(t, 3*n+2, err.(*Error).Column(), n)
+			require.Equal(t, []string{"'x'"}, err.(*Error).Expected(), n)
+		}
+	})
+
+	t.Run("can't be backtracked into", func(t *testing.T) {
+		ps := NewReaderState(strings.NewReader(input))
+		Some(item)(ps, &Result{})
+		require.True(t, ps.Offset > 0)
+
+		require.Panics(t, func() { ps.rewind(0) })
+	})
+
+	t.Run("kept for left recursion", func(t *testing.T) {
+		var count Parser
+		count = LeftRec(Any(
+			Seq(&count, ",", Cut(), "1").Map(func(n *Result) { n.Result = n.Child[0].Result.(int) + 1 }),
+			Bind("1", 1),
+		))
+
+		result, err := RunReader(count, strings.NewReader("1"+strings.Repeat(",1", 5000)))
+		require.NoError(t, err)
+		require.Equal(t, 5001, result)
+	})
+}
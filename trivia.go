@@ -0,0 +1,70 @@
+package goparsify
+
+// Trivia is the input KeepTrivia saw the grammar skip, keyed by the Start of the token that follows it. The input
+// after the last token is kept at the end of the input.
+type Trivia map[int]string
+
+// KeepTrivia keeps the input that the rest of the grammar skips, for formatters and other tools that need to write
+// the input back out. Every token, a Result with no children that matched something, gets the input between it and
+// the token before it in a Trivia. That is mostly whitespace and comments, but also any input the grammar matched
+// without keeping, like the separators given to Some and Many.
+//
+// The result is the parser's result in .Child[0], and an empty token at the end of the input in .Child[1] whose
+// .Result is the Trivia. .Result is passed through. The Token of every token is set to exactly the input it matched
+// and the Token of results that matched nothing, like Peek, is cleared, so writing out the trivia and Token of each
+// token followed by the trivia at the end reproduces the input byte for byte:
+//  trivia := node.Child[1].Result.(Trivia)
+//  buf.WriteString(trivia[token.Start] + token.Token)
+// The Token is usually what it already was, but not always, eg StringLit unescapes. Results built by Map see the
+// usual Tokens, as they are built before the trivia is attached.
+func KeepTrivia(parser Parserish) Parser {
+	p := Parsify(parser)
+
+	return describeAs(NewParser("KeepTrivia()", func(ps *State, node *Result) {
+		startpos := ps.Offset + ps.Pos
+		// the trivia is only collected at the end, so a stream has to keep everything until then
+		defer ps.release(ps.holdFrom(startpos))
+
+		inner := Result{}
+		p(ps, &inner)
+		if ps.Errored() {
+			return
+		}
+		ps.WS(ps)
+		if ps.Errored() {
+			ps.rewind(startpos)
+			return
+		}
+
+		end := ps.Offset + ps.Pos
+		prev := startpos
+		trivia := Trivia{}
+		attachTrivia(ps, &inner, trivia, &prev)
+		trivia[end] = ps.token(prev-ps.Offset, end-ps.Offset)
+		eof := Result{Start: end, End: end, Result: trivia}
+
+		*node = Result{Child: []Result{inner, eof}, Result: inner.Result, Start: startpos, End: end}
+	}), p)
+}
+
+// attachTrivia walks the tokens in order, prev is where the last one ended
+func attachTrivia(ps *State, node *Result, trivia Trivia, prev *int) {
+	if len(node.Child) > 0 {
+		for i := range node.Child {
+			attachTrivia(ps, &node.Child[i], trivia, prev)
+		}
+		return
+	}
+
+	if node.End <= node.Start {
+		// lookahead leaves the token it looked at without matching it
+		node.Token = ""
+		return
+	}
+	if node.Start < *prev {
+		return
+	}
+	trivia[node.Start] = ps.token(*prev-ps.Offset, node.Start-ps.Offset)
+	node.Token = ps.token(node.Start-ps.Offset, node.End-ps.Offset)
+	*prev = node.End
+}
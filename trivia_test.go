@@ -0,0 +1,128 @@
+package goparsify
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// reproduce writes every token kept by KeepTrivia back out with its trivia
+func reproduce(node *Result) string {
+	buf := &bytes.Buffer{}
+	trivia := node.Child[1].Result.(Trivia)
+	var walk func(node *Result)
+	walk = func(node *Result) {
+		for i := range node.Child {
+			walk(&node.Child[i])
+		}
+		if len(node.Child) == 0 && node.End > node.Start {
+			buf.WriteString(trivia[node.Start] + node.Token)
+		}
+	}
+	walk(&node.Child[0])
+	buf.WriteString(trivia[node.End])
+	return buf.String()
+}
+
+func TestKeepTrivia(t *testing.T) {
+	ws := WhitespaceWith("//", BlockComment("/*", "*/", false))
+	var value Parser
+	value = Any(
+		NumberLit(),
+		StringLit(`"`),
+		Seq("[", Cut(), Some(&value, ","), "]"),
+		Seq("{", Cut(), Some(Seq(StringLit(`"`), ":", &value), ","), "}"),
+	)
+	input := ` // config
+{
+	"a": [1, 2 /* two */, "th\"ree"],
+	"b"  :{} // empty
+}
+`
+
+	t.Run("round trips", func(t *testing.T) {
+		ps := NewState(input)
+		ps.WS = ws
+		node := Result{}
+		KeepTrivia(value)(ps, &node)
+		require.False(t, ps.Errored())
+		require.Equal(t, "", ps.Get())
+
+		require.Equal(t, input, reproduce(&node))
+	})
+
+	t.Run("tokens are the input as written", func(t *testing.T) {
+		unescaped := StringLit(`"`).Map(func(n *Result) { n.Result = n.Token })
+
+		node, ps := runParser(` "a\"b" `, KeepTrivia(unescaped))
+		require.False(t, ps.Errored())
+		require.Equal(t, `"a\"b"`, node.Child[0].Token)
+		require.Equal(t, `a"b`, node.Result)
+
+		placeholder := Recover(Seq("(", Cut(), "x", ")"), ")")
+		node, ps = runParser("(y)", KeepTrivia(placeholder))
+		require.False(t, ps.Errored())
+		require.Equal(t, "(y)", reproduce(&node))
+	})
+
+	t.Run("leading and trailing trivia", func(t *testing.T) {
+		ps := NewState(input)
+		ps.WS = ws
+		node := Result{}
+		KeepTrivia(value)(ps, &node)
+
+		trivia := node.Child[1].Result.(Trivia)
+
+		object := node.Child[0]
+		require.Equal(t, " // config\n", trivia[object.Child[0].Start])
+		require.Equal(t, "{", object.Child[0].Token)
+
+		array := object.Child[2].Child[0].Child[2]
+		require.Equal(t, " ", trivia[array.Child[0].Start])
+		require.Equal(t, ", ", trivia[array.Child[2].Child[1].Start])
+		require.Equal(t, " /* two */, ", trivia[array.Child[2].Child[2].Start])
+
+		eof := node.Child[1]
+		require.Equal(t, "\n", trivia[eof.Start])
+		require.Equal(t, len(input), eof.Start)
+	})
+
+	t.Run("lookahead", func(t *testing.T) {
+		input := " a b  a  b "
+		node, ps := runParser(input, KeepTrivia(Some(Seq("a", Peek("b"), "b"))))
+		require.False(t, ps.Errored())
+		require.Equal(t, "", node.Child[0].Child[0].Child[1].Token)
+		require.Equal(t, input, reproduce(&node))
+
+		node, ps = runParser(input, KeepTrivia(Some(Seq("a", Not("a"), "b"))))
+		require.False(t, ps.Errored())
+		require.Equal(t, input, reproduce(&node))
+	})
+
+	t.Run("passes results through", func(t *testing.T) {
+		result, err := Run(KeepTrivia(NumberLit()), " 1 ")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), result)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := Run(KeepTrivia(value), "[1, ")
+		require.Error(t, err)
+
+		_, err = Run(KeepTrivia(value), "[1] /*", ws)
+		require.EqualError(t, err, "1:7: expected '*/' to end the comment\n[1] /*\n      ^")
+	})
+
+	t.Run("streams", func(t *testing.T) {
+		big := "[" + strings.Repeat(" 1,", 5000) + " 1 ] "
+		ps := NewReaderState(iotest.HalfReader(strings.NewReader(big)))
+		node := Result{}
+		KeepTrivia(value)(ps, &node)
+		require.False(t, ps.Errored())
+
+		require.Equal(t, big, reproduce(&node))
+	})
+}
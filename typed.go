@@ -0,0 +1,146 @@
+//go:build go1.18
+// +build go1.18
+
+package goparsify
+
+import "fmt"
+
+// TypedParser is a Parser whose .Result is always a T. It is built from the same Parsers and Results as everything
+// else, so a TypedParser can be used anywhere a Parserish is accepted, and any Parserish can become one with Typed,
+// Token or Value. Grammars can adopt them one piece at a time:
+//  number := Typed[float64](NumberLit().Map(toFloat))
+//  pair := MapTo(Seq3(number, Token(","), number), func(t Tuple3[float64, string, float64]) point {
+//      return point{t.First, t.Third}
+//  })
+//  p, err := pair.Run("1, 2")
+type TypedParser[T any] func(*State, *Result)
+
+// Untyped returns the plain Parser, this is what Parsify uses
+func (p TypedParser[T]) Untyped() Parser {
+	return Parser(p)
+}
+
+// Run is Run with a typed result
+func (p TypedParser[T]) Run(input string, ws ...VoidParser) (T, error) {
+	result, err := Run(Parser(p), input, ws...)
+	v, _ := result.(T)
+	return v, err
+}
+
+// Tuple2 is the result of Seq2
+type Tuple2[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Tuple3 is the result of Seq3
+type Tuple3[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// valueOf gets the typed result of a node, or the zero value for placeholders left by Recover. Anything else that
+// isn't a T is a bug in the grammar, so it panics like Typed.
+func valueOf[T any](n *Result) T {
+	v, ok := n.Result.(T)
+	if !ok && !isPlaceholder(n.Result) {
+		panic(wrongType[T](n.Result))
+	}
+	return v
+}
+
+// isPlaceholder is whether result is what a typed parser may leave instead of a T: nothing, or an *Error from Recover
+func isPlaceholder(result interface{}) bool {
+	if result == nil {
+		return true
+	}
+	_, recovered := result.(*Error)
+	return recovered
+}
+
+func wrongType[T any](result interface{}) error {
+	return fmt.Errorf("expected parser to return a %T but got a %T", *new(T), result)
+}
+
+// Typed asserts that parser always sets .Result to a T. This is checked as the parser runs, and a parser that
+// sets anything else will panic, as it is a bug in the grammar rather than in the input.
+func Typed[T any](parser Parserish) TypedParser[T] {
+	p := Parsify(parser)
+
+	return func(ps *State, node *Result) {
+		p(ps, node)
+		if ps.Errored() {
+			return
+		}
+		if _, ok := node.Result.(T); !ok && !isPlaceholder(node.Result) {
+			panic(wrongType[T](node.Result))
+		}
+	}
+}
+
+// Token is a typed parser for the .Token matched by parser
+func Token(parser Parserish) TypedParser[string] {
+	return TypedParser[string](Map(parser, func(n *Result) {
+		n.Result = n.Token
+	}))
+}
+
+// Value is a typed Bind
+func Value[T any](parser Parserish, val T) TypedParser[T] {
+	return TypedParser[T](Bind(parser, val))
+}
+
+// Ref refers to a typed parser that has not been built yet, for recursive grammars. It is a *Parser, so it
+// counts towards MaxDepth and Describe follows it.
+func Ref[T any](parser *TypedParser[T]) TypedParser[T] {
+	return TypedParser[T](Parsify((*Parser)(parser)))
+}
+
+// MapTo converts the result of a typed parser
+func MapTo[A, B any](parser TypedParser[A], f func(A) B) TypedParser[B] {
+	return TypedParser[B](Map(Parser(parser), func(n *Result) {
+		n.Result = f(valueOf[A](n))
+	}))
+}
+
+// Seq2 is a typed Seq of two parsers
+func Seq2[A, B any](a TypedParser[A], b TypedParser[B]) TypedParser[Tuple2[A, B]] {
+	return TypedParser[Tuple2[A, B]](Seq(Parser(a), Parser(b)).Map(func(n *Result) {
+		n.Result = Tuple2[A, B]{valueOf[A](&n.Child[0]), valueOf[B](&n.Child[1])}
+	}))
+}
+
+// Seq3 is a typed Seq of three parsers
+func Seq3[A, B, C any](a TypedParser[A], b TypedParser[B], c TypedParser[C]) TypedParser[Tuple3[A, B, C]] {
+	return TypedParser[Tuple3[A, B, C]](Seq(Parser(a), Parser(b), Parser(c)).Map(func(n *Result) {
+		n.Result = Tuple3[A, B, C]{valueOf[A](&n.Child[0]), valueOf[B](&n.Child[1]), valueOf[C](&n.Child[2])}
+	}))
+}
+
+// AnyOf is a typed Any, every alternative must have the same type
+func AnyOf[T any](parsers ...TypedParser[T]) TypedParser[T] {
+	parserish := make([]Parserish, len(parsers))
+	for i, p := range parsers {
+		parserish[i] = Parser(p)
+	}
+	return TypedParser[T](Any(parserish...))
+}
+
+// ManyOf is a typed Many, collecting the results into a slice
+func ManyOf[T any](parser TypedParser[T], separator ...Parserish) TypedParser[[]T] {
+	return TypedParser[[]T](Many(Parser(parser), separator...).Map(collect[T]))
+}
+
+// SomeOf is a typed Some, collecting the results into a slice
+func SomeOf[T any](parser TypedParser[T], separator ...Parserish) TypedParser[[]T] {
+	return TypedParser[[]T](Some(Parser(parser), separator...).Map(collect[T]))
+}
+
+func collect[T any](n *Result) {
+	values := make([]T, len(n.Child))
+	for i := range n.Child {
+		values[i] = valueOf[T](&n.Child[i])
+	}
+	n.Result = values
+}
@@ -0,0 +1,106 @@
+//go:build go1.18
+// +build go1.18
+
+package goparsify
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type point struct {
+	X, Y int
+}
+
+func TestTyped(t *testing.T) {
+	number := MapTo(Token(Chars("0-9")), func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	})
+	pair := MapTo(Seq3(number, Token(","), number), func(t Tuple3[int, string, int]) point {
+		return point{t.First, t.Third}
+	})
+
+	t.Run("seq", func(t *testing.T) {
+		p, err := pair.Run("1, 2")
+		require.NoError(t, err)
+		require.Equal(t, point{1, 2}, p)
+
+		tuple, err := Seq2(number, Token("x")).Run("3x")
+		require.NoError(t, err)
+		require.Equal(t, Tuple2[int, string]{3, "x"}, tuple)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		p, err := pair.Run("1, x")
+		require.EqualError(t, err, "1:4: expected 0-9\n1, x\n   ^")
+		require.Equal(t, point{}, p)
+	})
+
+	t.Run("any and many", func(t *testing.T) {
+		points := SomeOf(AnyOf(pair, Value("origin", point{})), ";")
+		ps, err := points.Run("1,2; origin; 3,4")
+		require.NoError(t, err)
+		require.Equal(t, []point{{1, 2}, {0, 0}, {3, 4}}, ps)
+
+		ps, err = SomeOf(pair).Run("")
+		require.NoError(t, err)
+		require.Equal(t, []point{}, ps)
+
+		_, err = ManyOf(pair).Run("")
+		require.Error(t, err)
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		var list TypedParser[int]
+		list = AnyOf(
+			MapTo(Seq3(Token("("), Ref(&list), Token(")")), func(t Tuple3[string, int, string]) int { return t.Second + 1 }),
+			Value("x", 0),
+		)
+		depth, err := list.Run("(((x)))")
+		require.NoError(t, err)
+		require.Equal(t, 3, depth)
+
+		ps := NewState("(((x)))")
+		ps.MaxDepth = 2
+		_, err = RunState(list, ps)
+		require.IsType(t, &DepthLimitError{}, err)
+	})
+
+	t.Run("recursive grammars can be described", func(t *testing.T) {
+		defer recordDescriptions()()
+
+		var list TypedParser[int]
+		list = AnyOf(
+			MapTo(Seq3(Token("("), Ref(&list), Token(")")), func(t Tuple3[string, int, string]) int { return t.Second + 1 }),
+			Value("x", 0),
+		)
+		require.Equal(t, "grammar ::= \"(\" grammar \")\" | \"x\"\n", Describe(list).EBNF())
+	})
+
+	t.Run("mixed with untyped parsers", func(t *testing.T) {
+		result, err := Run(Seq("(", pair, ")").Map(func(n *Result) { n.Result = n.Child[1].Result }), "(1,2)")
+		require.NoError(t, err)
+		require.Equal(t, point{1, 2}, result)
+
+		sum := MapTo(Seq2(Typed[int64](NumberLit()), Typed[int64](NumberLit())), func(t Tuple2[int64, int64]) int64 {
+			return t.First + t.Second
+		})
+		total, err := sum.Run("1 2")
+		require.NoError(t, err)
+		require.Equal(t, int64(3), total)
+
+		require.Panics(t, func() {
+			_, _ = Typed[string](NumberLit()).Run("1")
+		})
+	})
+
+	t.Run("mistyped conversions", func(t *testing.T) {
+		mistyped := Seq2(TypedParser[string](NumberLit()), Token(","))
+		require.PanicsWithError(t, "expected parser to return a string but got a int64", func() {
+			_, _ = mistyped.Run("1,")
+		})
+	})
+}
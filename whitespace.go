@@ -0,0 +1,108 @@
+package goparsify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WhitespaceWith builds a whitespace parser that skips comments as well as unicode whitespace. Each comment is
+// either a string that starts a comment running to the end of the line, or a VoidParser like BlockComment:
+//  ps.WS = WhitespaceWith("//", "#", BlockComment("/*", "*/", false))
+func WhitespaceWith(comments ...interface{}) VoidParser {
+	var lineComments []string
+	var others []VoidParser
+	for _, comment := range comments {
+		switch comment := comment.(type) {
+		case string:
+			lineComments = append(lineComments, comment)
+		case VoidParser:
+			others = append(others, comment)
+		case func(*State):
+			others = append(others, comment)
+		default:
+			panic(fmt.Errorf("cant turn a `%T` into a comment", comment))
+		}
+	}
+
+	skip := func(ps *State) {
+		for {
+			UnicodeWhitespace(ps)
+			start := ps.Pos
+
+			for _, prefix := range lineComments {
+				if ps.hasPrefixAt(ps.Pos, prefix) {
+					ps.Advance(len(prefix))
+					for ps.has(ps.Pos) && ps.Input[ps.Pos] != '\n' {
+						ps.Pos++
+					}
+				}
+			}
+
+			for _, comment := range others {
+				comment(ps)
+				if ps.Errored() {
+					return
+				}
+			}
+
+			if ps.Pos == start {
+				return
+			}
+		}
+	}
+
+	return func(ps *State) {
+		if !ps.Errored() {
+			skip(ps)
+			return
+		}
+		// Label skips whitespace after its parser has failed, so the comments need a clean slate to tell if they
+		// fail, and the error has to be put back when they don't
+		failed := ps.Error
+		ps.Error.expected = ""
+		skip(ps)
+		if !ps.Errored() {
+			ps.Error = failed
+		}
+	}
+}
+
+// BlockComment skips a comment between open and close, eg BlockComment("/*", "*/", false). When nested is set
+// every open needs its own close, so commenting out code that already has comments in it works. A comment that
+// is never closed is an error. It only matches a single comment, see WhitespaceWith.
+func BlockComment(open, close string, nested bool) VoidParser {
+	expected := "'" + close + "' to end the comment"
+
+	return func(ps *State) {
+		if !ps.hasPrefixAt(ps.Pos, open) {
+			return
+		}
+
+		pos := ps.Pos + len(open)
+		depth := 1
+		for depth > 0 {
+			switch {
+			case ps.hasPrefixAt(pos, close):
+				pos += len(close)
+				depth--
+			case nested && ps.hasPrefixAt(pos, open):
+				pos += len(open)
+				depth++
+			case ps.has(pos):
+				pos++
+			default:
+				ps.ErrorAt(pos, expected)
+				return
+			}
+		}
+		ps.Pos = pos
+	}
+}
+
+// hasPrefixAt checks for prefix at pos in Input, reading more of the stream if it needs to
+func (s *State) hasPrefixAt(pos int, prefix string) bool {
+	if len(s.Input)-pos < len(prefix) {
+		s.fill(pos + len(prefix))
+	}
+	return strings.HasPrefix(s.Input[pos:], prefix)
+}
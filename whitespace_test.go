@@ -0,0 +1,79 @@
+package goparsify
+
+import (
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhitespaceWith(t *testing.T) {
+	ws := WhitespaceWith("//", "#", BlockComment("/*", "*/", false))
+	list := Some(Chars("a-z"), ",").Map(func(n *Result) { n.Result = len(n.Child) })
+
+	t.Run("skips comments", func(t *testing.T) {
+		result, err := Run(list, `a, // one
+			b /* two */, /* three */ /**/c # four
+			// five`, ws)
+		require.NoError(t, err)
+		require.Equal(t, 3, result)
+	})
+
+	t.Run("reads comments from streams", func(t *testing.T) {
+		input := "a /* x */, # y\nb"
+		result, err := RunReader(list, iotest.OneByteReader(strings.NewReader(input)), ws)
+		require.NoError(t, err)
+		require.Equal(t, 2, result)
+	})
+
+	t.Run("unterminated block comment", func(t *testing.T) {
+		_, err := Run(list, "a, b /* c,\nd", ws)
+		require.EqualError(t, err, "2:2: expected '*/' to end the comment\nd\n ^")
+	})
+
+	t.Run("unterminated comment between tokens", func(t *testing.T) {
+		_, err := Run(Seq("a", "b"), "a /* b", ws)
+		require.EqualError(t, err, "1:7: expected '*/' to end the comment\na /* b\n      ^")
+	})
+
+	t.Run("labels after a comment", func(t *testing.T) {
+		_, err := Run(Label(Chars("a-z"), "identifier"), "/* c */ 1", WhitespaceWith(BlockComment("/*", "*/", false)))
+		require.EqualError(t, err, "1:9: expected identifier\n/* c */ 1\n        ^")
+	})
+
+	t.Run("bad comments", func(t *testing.T) {
+		require.Panics(t, func() {
+			WhitespaceWith(1)
+		})
+	})
+}
+
+func TestBlockComment(t *testing.T) {
+	t.Run("flat", func(t *testing.T) {
+		ps := NewState("/* a /* b */ c */")
+		BlockComment("/*", "*/", false)(ps)
+		require.False(t, ps.Errored())
+		require.Equal(t, " c */", ps.Get())
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		ps := NewState("/* a /* b */ c */ d")
+		BlockComment("/*", "*/", true)(ps)
+		require.False(t, ps.Errored())
+		require.Equal(t, " d", ps.Get())
+
+		ps = NewState("/* a /* b */ c")
+		BlockComment("/*", "*/", true)(ps)
+		require.True(t, ps.Errored())
+		require.Equal(t, 14, ps.Error.Pos())
+		require.Equal(t, 0, ps.Pos)
+	})
+
+	t.Run("not a comment", func(t *testing.T) {
+		ps := NewState("a /* b */")
+		BlockComment("/*", "*/", false)(ps)
+		require.False(t, ps.Errored())
+		require.Equal(t, 0, ps.Pos)
+	})
+}